@@ -0,0 +1,70 @@
+package driversetup
+
+import (
+	"fmt"
+	"host/webdriver"
+	"os/exec"
+)
+
+// Browser is a browser the tests can run in, together with its driver.
+//
+// Each implementation knows where its files live, how to download them,
+// how to start its driver and which capabilities a new session needs.
+type Browser interface {
+	// Name used in the CLI and in the Roc Config, also the W3C browserName
+	Name() string
+	// Version of the browser and the driver - used e.g. as a cache key in CI
+	Version() string
+	// BrowserPath returns the browser binary - empty when the driver should find the browser itself
+	BrowserPath() (string, error)
+	// Download fetches the browser and the driver when they are missing
+	Download() error
+	// RunDriver runs the driver on the given port and listens for crashes
+	RunDriver(port int) (*exec.Cmd, error)
+	// Capabilities returns the vendor specific capabilities of a new session
+	Capabilities(settings BrowserSettings) map[string]interface{}
+	// ConsoleLogs returns the console messages and JavaScript errors logged since the last call
+	ConsoleLogs(sessionId string) ([]webdriver.LogEntry, error)
+	// DevToolsAddress returns the "host:port" of the Chrome DevTools Protocol endpoint of a session
+	DevToolsAddress(capabilities map[string]interface{}) (string, error)
+}
+
+// BrowserSettings are the session settings every browser has to support.
+type BrowserSettings struct {
+	// path to the browser binary - leave empty to let the driver choose (e.g. on a remote grid)
+	BrowserPath string
+	Headless    bool
+	// "<width>,<height>"
+	WindowSize string
+	// device to emulate - nil for a desktop browser
+	MobileEmulation *webdriver.MobileEmulation
+	// e.g. "de-DE" - empty for the browser default
+	Locale string
+	// extra Chrome options from the config and the cli - ignored by Firefox
+	ChromeOptions ChromeOptions
+	// absolute path where the browser saves the downloads - empty for the browser default
+	DownloadDir string
+}
+
+// ChromeOptions are the extra options for Chrome from the config and the cli.
+type ChromeOptions struct {
+	Args            []string               `json:"args"`
+	Prefs           map[string]interface{} `json:"prefs"`
+	ExcludeSwitches []string               `json:"excludeSwitches"`
+	// paths to unpacked extensions
+	Extensions []string `json:"extensions"`
+}
+
+const DefaultBrowserName = "chrome"
+
+// GetBrowser returns the Browser with the given name.
+func GetBrowser(name string) (Browser, error) {
+	switch name {
+	case "chrome":
+		return Chrome{}, nil
+	case "firefox":
+		return Firefox{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported browser \"%s\" - supported browsers: chrome, firefox", name)
+	}
+}
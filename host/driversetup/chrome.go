@@ -0,0 +1,171 @@
+package driversetup
+
+import (
+	"errors"
+	"fmt"
+	"host/setup"
+	"host/utils"
+	"host/webdriver"
+	"os/exec"
+	"strings"
+)
+
+// Chrome runs tests in "chrome for testing" with chromedriver.
+type Chrome struct{}
+
+func (Chrome) Name() string {
+	return "chrome"
+}
+
+func (Chrome) Version() string {
+	return setup.BrowserVersion
+}
+
+func (Chrome) BrowserPath() (string, error) {
+	paths, err := setup.GetChromePaths()
+	if err != nil {
+		return "", err
+	}
+
+	return paths.BrowserPath, nil
+}
+
+func (Chrome) Download() error {
+	paths, err := setup.GetChromePaths()
+	if err != nil {
+		return err
+	}
+
+	if doesFileOrDirExist(paths.BrowserPath) && doesFileOrDirExist(paths.DriverPath) {
+		// fmt.Println(utils.FG_BLUE + "Browser is ready" + utils.RESET)
+		return nil
+	}
+
+	// fmt.Println("chrome or driver missing ")
+	fmt.Println(utils.FG_BLUE + "Driver or/and Browser is/are missing..." + utils.RESET)
+	fmt.Println(utils.FG_BLUE + "Downloading Driver and Browser." + utils.RESET)
+
+	err = checkAndCreateDir(paths.DirPath)
+	if err != nil {
+		return err
+	}
+	// checkAndCreateDir(browserFilesDir)
+
+	chromeUrl := fmt.Sprintf("https://storage.googleapis.com/chrome-for-testing-public/%s/%s/chrome-%s.zip", paths.BrowserVersion, paths.OsName, paths.OsName)
+	driverUrl := fmt.Sprintf("https://storage.googleapis.com/chrome-for-testing-public/%s/%s/chromedriver-%s.zip", paths.BrowserVersion, paths.OsName, paths.OsName)
+
+	// downloadFile("chrome.zip", chromeUrl)
+	// fmt.Println("downloaded")
+	err = downloadFile(fmt.Sprintf("%s.zip", paths.BrowserDirPath), chromeUrl)
+	if err != nil {
+		return err
+	}
+	err = downloadFile(fmt.Sprintf("%s.zip", paths.DriverDirPath), driverUrl)
+	if err != nil {
+		return err
+	}
+
+	err = unzip(fmt.Sprintf("%s.zip", paths.BrowserDirPath), fmt.Sprintf("%s/", paths.DirPath))
+	if err != nil {
+		return err
+	}
+
+	err = unzip(fmt.Sprintf("%s.zip", paths.DriverDirPath), fmt.Sprintf("%s/", paths.DirPath))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (Chrome) RunDriver(port int) (*exec.Cmd, error) {
+	paths, err := setup.GetChromePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	// cmd := exec.Command(paths.DriverPath, "--disable-dev-shm-usage")
+	// cmd := exec.Command(paths.DriverPath, "--verbose")
+	return runDriver("Chromedriver", paths.DriverPath, fmt.Sprintf("--port=%d", port))
+}
+
+func (Chrome) Capabilities(settings BrowserSettings) map[string]interface{} {
+	binaryArgs := []string{
+		"--window-size=" + settings.WindowSize,
+	}
+
+	if settings.Headless {
+		binaryArgs = append(binaryArgs, "--headless")
+	}
+
+	if settings.Locale != "" {
+		binaryArgs = append(binaryArgs, "--lang="+settings.Locale)
+	}
+
+	if len(settings.ChromeOptions.Extensions) > 0 {
+		binaryArgs = append(binaryArgs, "--load-extension="+strings.Join(settings.ChromeOptions.Extensions, ","))
+	}
+
+	// the extra args go last, so they can replace the ones above
+	binaryArgs = append(binaryArgs, settings.ChromeOptions.Args...)
+
+	chromeOptions := map[string]interface{}{
+		"args": binaryArgs,
+	}
+
+	if settings.BrowserPath != "" {
+		chromeOptions["binary"] = settings.BrowserPath
+	}
+
+	if settings.MobileEmulation != nil {
+		chromeOptions["mobileEmulation"] = settings.MobileEmulation
+	}
+
+	prefs := map[string]interface{}{}
+	for name, value := range settings.ChromeOptions.Prefs {
+		prefs[name] = value
+	}
+
+	if settings.Locale != "" {
+		prefs["intl.accept_languages"] = settings.Locale
+	}
+
+	if settings.DownloadDir != "" {
+		prefs["download.default_directory"] = settings.DownloadDir
+		prefs["download.prompt_for_download"] = false
+		prefs["download.directory_upgrade"] = true
+	}
+
+	if len(prefs) > 0 {
+		chromeOptions["prefs"] = prefs
+	}
+
+	if len(settings.ChromeOptions.ExcludeSwitches) > 0 {
+		chromeOptions["excludeSwitches"] = settings.ChromeOptions.ExcludeSwitches
+	}
+
+	return map[string]interface{}{
+		"goog:chromeOptions": chromeOptions,
+		"goog:loggingPrefs": map[string]interface{}{
+			"browser": "ALL",
+		},
+	}
+}
+
+func (Chrome) ConsoleLogs(sessionId string) ([]webdriver.LogEntry, error) {
+	return webdriver.GetLogs(sessionId, "browser")
+}
+
+func (Chrome) DevToolsAddress(capabilities map[string]interface{}) (string, error) {
+	chromeOptions, ok := capabilities["goog:chromeOptions"].(map[string]interface{})
+	if !ok {
+		return "", errors.New("the session has no goog:chromeOptions capability")
+	}
+
+	debuggerAddress, ok := chromeOptions["debuggerAddress"].(string)
+	if !ok || debuggerAddress == "" {
+		return "", errors.New("the session has no DevTools debuggerAddress")
+	}
+
+	return debuggerAddress, nil
+}
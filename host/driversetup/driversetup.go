@@ -1,43 +1,44 @@
 package driversetup
 
 import (
+	"archive/tar"
 	"archive/zip"
 	"fmt"
-	"host/setup"
-	"host/utils"
 	"host/webdriver"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
-// RunChromedriver runs the chromedriver and listens for crashes
-func RunChromedriver() (*exec.Cmd, error) {
-	paths, err := setup.GetChromePaths()
+// GetFreePort asks the OS for a free local port,
+// so parallel test runs on one machine don't share the same driver.
+func GetFreePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
 
-	// Create the command to run ./chromedriver
-	// cmd := exec.Command(paths.DriverPath, "--disable-dev-shm-usage")
-	cmd := exec.Command(paths.DriverPath)
-	// cmd := exec.Command(paths.DriverPath, "--verbose")
+// runDriver runs the driver binary in the background and listens for crashes
+func runDriver(name, driverPath string, args ...string) (*exec.Cmd, error) {
+	cmd := exec.Command(driverPath, args...)
 
 	// cmd.Stdout = os.Stdout
 	// cmd.Stderr = os.Stderr
 
 	// Start the process in the background
 	if err := cmd.Start(); err != nil {
-		// log.Fatalf("Failed to start chromedriver: %v", err)
 		return nil, err
 	}
 
-	// Log that the process has started
-	// fmt.Println("Chromedriver started with PID:", cmd.Process.Pid)
-
 	// Create a goroutine to wait for the process to exit or crash
 	go func() {
 		// Wait for the process to exit
@@ -45,9 +46,9 @@ func RunChromedriver() (*exec.Cmd, error) {
 
 		// If the process exits or crashes, handle it here
 		if err != nil {
-			fmt.Println("Chromedriver crashed:", err)
+			fmt.Printf("%s crashed: %v\n", name, err)
 		} else {
-			fmt.Println("Chromedriver exited normally")
+			fmt.Printf("%s exited normally\n", name)
 		}
 	}()
 
@@ -77,7 +78,7 @@ func WaitForDriverReady(timeout time.Duration) error {
 	}
 }
 
-// handleCleanup ensures chromedriver is killed when the app exits
+// handleCleanup ensures the driver is killed when the app exits
 func HandleCleanup(cmd *exec.Cmd) error {
 	if cmd != nil && cmd.Process != nil {
 		// Kill the process if it's running
@@ -89,54 +90,6 @@ func HandleCleanup(cmd *exec.Cmd) error {
 	return nil
 }
 
-func DownloadChromeAndDriver() error {
-	paths, err := setup.GetChromePaths()
-	if err != nil {
-		return err
-	}
-
-	if doesFileOrDirExist(paths.BrowserPath) && doesFileOrDirExist(paths.DriverPath) {
-		// fmt.Println(utils.FG_BLUE + "Browser is ready" + utils.RESET)
-		return nil
-	}
-
-	// fmt.Println("chrome or driver missing ")
-	fmt.Println(utils.FG_BLUE + "Driver or/and Browser is/are missing..." + utils.RESET)
-	fmt.Println(utils.FG_BLUE + "Downloading Driver and Browser." + utils.RESET)
-
-	err = checkAndCreateDir(paths.DirPath)
-	if err != nil {
-		return err
-	}
-	// checkAndCreateDir(browserFilesDir)
-
-	chromeUrl := fmt.Sprintf("https://storage.googleapis.com/chrome-for-testing-public/%s/%s/chrome-%s.zip", paths.BrowserVersion, paths.OsName, paths.OsName)
-	driverUrl := fmt.Sprintf("https://storage.googleapis.com/chrome-for-testing-public/%s/%s/chromedriver-%s.zip", paths.BrowserVersion, paths.OsName, paths.OsName)
-
-	// downloadFile("chrome.zip", chromeUrl)
-	// fmt.Println("downloaded")
-	err = downloadFile(fmt.Sprintf("%s.zip", paths.BrowserDirPath), chromeUrl)
-	if err != nil {
-		return err
-	}
-	err = downloadFile(fmt.Sprintf("%s.zip", paths.DriverDirPath), driverUrl)
-	if err != nil {
-		return err
-	}
-
-	err = unzip(fmt.Sprintf("%s.zip", paths.BrowserDirPath), fmt.Sprintf("%s/", paths.DirPath))
-	if err != nil {
-		return err
-	}
-
-	err = unzip(fmt.Sprintf("%s.zip", paths.DriverDirPath), fmt.Sprintf("%s/", paths.DirPath))
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 // Unzip function extracts a zip file to the specified destination folder
 func unzip(src, dest string) error {
 	// Open the zip file
@@ -149,7 +102,10 @@ func unzip(src, dest string) error {
 	// Iterate through each file in the zip archive
 	for _, file := range r.File {
 		// Construct the full path for the destination
-		filePath := filepath.Join(dest, file.Name)
+		filePath, err := archiveEntryPath(dest, file.Name)
+		if err != nil {
+			return err
+		}
 
 		// If the file is a directory, create the directory
 		if file.FileInfo().IsDir() {
@@ -188,6 +144,92 @@ func unzip(src, dest string) error {
 	return nil
 }
 
+// untar extracts a tar archive to the specified destination folder,
+// decompress wraps the archive file - e.g. with gzip or bzip2
+func untar(src, dest string, decompress func(io.Reader) (io.Reader, error)) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := decompress(file)
+	if err != nil {
+		return err
+	}
+
+	tarReader := tar.NewReader(reader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		filePath, err := archiveEntryPath(dest, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(filePath, os.ModePerm); err != nil {
+				return err
+			}
+
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+				return err
+			}
+
+			// the link could be used to write outside of dest by the next entries
+			if filepath.IsAbs(header.Linkname) {
+				return fmt.Errorf("the archive link %s points outside of the destination: %s", header.Name, header.Linkname)
+			}
+			if _, err := archiveEntryPath(dest, filepath.Join(filepath.Dir(header.Name), header.Linkname)); err != nil {
+				return err
+			}
+
+			if err := os.Symlink(header.Linkname, filePath); err != nil && !os.IsExist(err) {
+				return err
+			}
+
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+				return err
+			}
+
+			destFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, header.FileInfo().Mode())
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(destFile, tarReader)
+			destFile.Close()
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// archiveEntryPath returns the extraction path of an archive entry,
+// the entries with paths like "../../bin/sh" are rejected
+func archiveEntryPath(dest, name string) (string, error) {
+	filePath := filepath.Join(dest, name)
+
+	relPath, err := filepath.Rel(filepath.Clean(dest), filePath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("the archive entry %s points outside of the destination", name)
+	}
+
+	return filePath, nil
+}
+
 func doesFileOrDirExist(dir string) bool {
 	_, err := os.Stat(dir)
 
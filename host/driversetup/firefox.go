@@ -0,0 +1,169 @@
+package driversetup
+
+import (
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"host/setup"
+	"host/utils"
+	"host/webdriver"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Firefox runs tests in Firefox with geckodriver.
+type Firefox struct{}
+
+func (Firefox) Name() string {
+	return "firefox"
+}
+
+func (Firefox) Version() string {
+	return setup.FirefoxBrowserVersion
+}
+
+func (Firefox) BrowserPath() (string, error) {
+	paths, err := setup.GetFirefoxPaths()
+	if err != nil {
+		return "", err
+	}
+
+	return paths.BrowserPath, nil
+}
+
+func (Firefox) Download() error {
+	paths, err := setup.GetFirefoxPaths()
+	if err != nil {
+		return err
+	}
+
+	isBrowserReady := paths.BrowserPath == "" || doesFileOrDirExist(paths.BrowserPath)
+	if isBrowserReady && doesFileOrDirExist(paths.DriverPath) {
+		return nil
+	}
+
+	fmt.Println(utils.FG_BLUE + "Driver or/and Browser is/are missing..." + utils.RESET)
+	fmt.Println(utils.FG_BLUE + "Downloading Driver and Browser." + utils.RESET)
+
+	err = checkAndCreateDir(paths.DriverDirPath)
+	if err != nil {
+		return err
+	}
+
+	if paths.BrowserPath != "" && !doesFileOrDirExist(paths.BrowserPath) {
+		firefoxUrl := fmt.Sprintf("https://ftp.mozilla.org/pub/firefox/releases/%s/%s/en-US/firefox-%s.tar.bz2", paths.BrowserVersion, paths.OsName, paths.BrowserVersion)
+		firefoxArchive := fmt.Sprintf("%s.tar.bz2", paths.BrowserDirPath)
+
+		err = downloadFile(firefoxArchive, firefoxUrl)
+		if err != nil {
+			return err
+		}
+
+		err = untar(firefoxArchive, fmt.Sprintf("%s/", paths.DirPath), func(r io.Reader) (io.Reader, error) {
+			return bzip2.NewReader(r), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// geckodriver is released as a single binary - a zip on Windows, a tar.gz everywhere else
+	driverUrl := fmt.Sprintf("https://github.com/mozilla/geckodriver/releases/download/v%s/geckodriver-v%s-%s", paths.DriverVersion, paths.DriverVersion, paths.DriverOsName)
+
+	if runtime.GOOS == "windows" {
+		driverArchive := fmt.Sprintf("%s.zip", paths.DriverDirPath)
+
+		err = downloadFile(driverArchive, driverUrl+".zip")
+		if err != nil {
+			return err
+		}
+
+		return unzip(driverArchive, paths.DriverDirPath)
+	}
+
+	driverArchive := fmt.Sprintf("%s.tar.gz", paths.DriverDirPath)
+
+	err = downloadFile(driverArchive, driverUrl+".tar.gz")
+	if err != nil {
+		return err
+	}
+
+	return untar(driverArchive, paths.DriverDirPath, func(r io.Reader) (io.Reader, error) {
+		return gzip.NewReader(r)
+	})
+}
+
+func (Firefox) RunDriver(port int) (*exec.Cmd, error) {
+	paths, err := setup.GetFirefoxPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	return runDriver("Geckodriver", paths.DriverPath, fmt.Sprintf("--port=%d", port))
+}
+
+func (Firefox) Capabilities(settings BrowserSettings) map[string]interface{} {
+	binaryArgs := []string{}
+
+	windowSize := settings.WindowSize
+	if settings.MobileEmulation != nil {
+		// Firefox has no device emulation - it is approximated with the window size and the user agent
+		windowSize = fmt.Sprintf("%d,%d", settings.MobileEmulation.DeviceMetrics.Width, settings.MobileEmulation.DeviceMetrics.Height)
+	}
+
+	width, height, found := strings.Cut(windowSize, ",")
+	if found {
+		binaryArgs = append(binaryArgs, "--width="+width, "--height="+height)
+	}
+
+	if settings.Headless {
+		binaryArgs = append(binaryArgs, "-headless")
+	}
+
+	firefoxOptions := map[string]interface{}{
+		"args": binaryArgs,
+	}
+
+	if settings.BrowserPath != "" {
+		firefoxOptions["binary"] = settings.BrowserPath
+	}
+
+	prefs := map[string]interface{}{}
+
+	if settings.MobileEmulation != nil && settings.MobileEmulation.UserAgent != "" {
+		prefs["general.useragent.override"] = settings.MobileEmulation.UserAgent
+	}
+
+	if settings.Locale != "" {
+		prefs["intl.accept_languages"] = settings.Locale
+		prefs["intl.locale.requested"] = settings.Locale
+	}
+
+	if settings.DownloadDir != "" {
+		// 2 - use the custom directory from "browser.download.dir"
+		prefs["browser.download.folderList"] = 2
+		prefs["browser.download.dir"] = settings.DownloadDir
+		prefs["browser.download.useDownloadDir"] = true
+		prefs["browser.download.always_ask_before_handling_new_types"] = false
+	}
+
+	if len(prefs) > 0 {
+		firefoxOptions["prefs"] = prefs
+	}
+
+	return map[string]interface{}{
+		"moz:firefoxOptions": firefoxOptions,
+	}
+}
+
+// geckodriver has no log endpoint - console logs are not collected in Firefox
+func (Firefox) ConsoleLogs(sessionId string) ([]webdriver.LogEntry, error) {
+	return nil, errors.New("console logs are not supported in Firefox")
+}
+
+func (Firefox) DevToolsAddress(capabilities map[string]interface{}) (string, error) {
+	return "", errors.New("the Chrome DevTools Protocol is not supported in Firefox")
+}
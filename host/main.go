@@ -3,18 +3,36 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
 
 	// these modules are generated by glue when we run `roc build.roc` - not using roc glue - don't know how :(
 	"host/roc"
 )
 
+// stringList is a flag that can be used multiple times
+type stringList []string
+
+func (list *stringList) String() string {
+	return strings.Join(*list, " ")
+}
+
+func (list *stringList) Set(value string) error {
+	*list = append(*list, value)
+	return nil
+}
+
 func entry() {
-	setupOnly := flag.Bool("setup", false, "run only browser and driver setup (useful in CI)")
-	printBrowserVersionOnly := flag.Bool("print-browser-version-only", false, "print the version of used broweser (useful in CI)")
+	setupOnly := flag.Bool("setup", false, "run only browser and driver setup for the browser from the config or --browser (useful in CI)")
+	printBrowserVersionOnly := flag.Bool("print-browser-version-only", false, "print the version of the browser from the config or --browser (useful in CI)")
 	verbose := flag.Bool("verbose", false, "run with pauses between actions and visualize actions in browser")
 	debugMode := flag.Bool("debug", false, "run with pauses between actions and visualize actions in browser")
 	headless := flag.Bool("headless", false, "run headless")
 	testFilterName := flag.String("name", "", "run only tests containing specified string")
+	projectFilterName := flag.String("project", "", "run only projects containing specified string")
+	driverUrl := flag.String("driver-url", os.Getenv("R2E_DRIVER_URL"), "use an already running WebDriver endpoint, e.g. a Selenium Grid (env: R2E_DRIVER_URL)")
+	browser := flag.String("browser", os.Getenv("R2E_BROWSER"), "run in \"chrome\" or \"firefox\" - overrides the browser from the config (env: R2E_BROWSER)")
+	var chromeArgs stringList
+	flag.Var(&chromeArgs, "chrome-arg", "an extra Chrome argument, e.g. --chrome-arg=--disable-gpu (can be used multiple times)")
 
 	flag.Parse()
 
@@ -25,6 +43,10 @@ func entry() {
 		DebugMode:               *debugMode,
 		Headless:                *headless,
 		TestNameFilter:          *testFilterName,
+		ProjectFilter:           *projectFilterName,
+		DriverUrl:               *driverUrl,
+		Browser:                 *browser,
+		ChromeArgs:              chromeArgs,
 	}
 
 	exitCode := roc.Main(options)
@@ -7,14 +7,18 @@ package roc
 import "C"
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"host/driversetup"
-	"host/setup"
 	"host/utils"
 	"host/webdriver"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 	"unsafe"
 )
@@ -26,6 +30,12 @@ type Options struct {
 	Verbose                 bool
 	DebugMode               bool
 	TestNameFilter          string
+	ProjectFilter           string
+	DriverUrl               string
+	// overrides the browser from the Roc Config when not empty
+	Browser string
+	// appended to the Chrome args from the Roc Config
+	ChromeArgs []string
 }
 
 var options = Options{
@@ -35,6 +45,10 @@ var options = Options{
 	Headless:                false,
 	DebugMode:               false,
 	TestNameFilter:          "",
+	ProjectFilter:           "",
+	DriverUrl:               "",
+	Browser:                 "",
+	ChromeArgs:              []string{},
 }
 
 type OptionsFromUserApp struct {
@@ -43,6 +57,13 @@ type OptionsFromUserApp struct {
 	ScriptExecutionTimeout uint64
 	ElementImplicitTimeout uint64
 	WindowSize             string
+	Browser                string
+	// JSON webdriver.NetworkConditions
+	NetworkConditions string
+	// JSON webdriver.MobileEmulation - empty for a desktop browser
+	Device string
+	// JSON driversetup.ChromeOptions - empty when not set
+	ChromeOptions string
 }
 
 type TestOverrides struct {
@@ -51,10 +72,15 @@ type TestOverrides struct {
 	ScriptExecutionTimeout *uint64
 	ElementImplicitTimeout *uint64
 	WindowSize             *string
+	NetworkConditions      *string
+	Device                 *string
+	// set by the projects - there is no global locale, the browser default is used
+	Locale   *string
+	Headless *bool
 }
 
 var optionsFromUserApp = OptionsFromUserApp{
-	// set by the UserApp in roc_fx_setTimeouts and roc_fx_setWindowSize
+	// set by the UserApp in roc_fx_setTimeouts, roc_fx_setWindowSize and roc_fx_set_browser
 }
 
 var testOverrides = TestOverrides{
@@ -64,50 +90,114 @@ var testOverrides = TestOverrides{
 func Main(cliOptions Options) int {
 	options = cliOptions
 
-	if options.PrintBrowserVersionOnly {
-		fmt.Printf("%s", setup.BrowserVersion)
-		return 0
+	if options.Browser != "" {
+		_, err := driversetup.GetBrowser(options.Browser)
+		if err != nil {
+			fmt.Println(utils.FG_RED+"Setup failed with: "+utils.RESET, err)
+			return 1
+		}
+	}
+
+	if options.DriverUrl != "" {
+		// the driver and the browser are managed by someone else, e.g. a Selenium Grid
+		webdriver.SetBaseUrl(options.DriverUrl)
 	}
 
-	err := driversetup.DownloadChromeAndDriver()
+	// with --setup and --print-browser-version-only the Roc app only sets the config and calls roc_fx_run_setup,
+	// because the browser from the Roc Config is not known before
+	size := C.roc__main_for_host_1_exposed_size()
+	capturePtr := roc_alloc(size, 0)
+	defer roc_dealloc(capturePtr, 0)
+
+	result := C.roc__main_for_host_1_exposed()
+
+	// TODO - error handling
+	err := driversetup.HandleCleanup(driverCmd)
 	if err != nil {
-		fmt.Println(utils.FG_RED+"Setup failed with: "+utils.RESET, err)
+		fmt.Println("could not kill the driver: ", err)
 		return 1
 	}
 
-	if options.SetupOnly {
-		fmt.Println("Browser and driver ready.")
-		return 0
+	return (*(*int)(unsafe.Pointer(&result)))
+}
+
+// getBrowser returns the browser selected in the CLI, or in the Roc Config - chrome by default
+func getBrowser() (driversetup.Browser, error) {
+	name := options.Browser
+	if name == "" {
+		name = optionsFromUserApp.Browser
 	}
 
-	cmd, err := driversetup.RunChromedriver()
-	if err != nil {
-		// todo
-		fmt.Println("could not run chrome: ", err)
-		return 1
+	if name == "" {
+		name = driversetup.DefaultBrowserName
 	}
 
-	err = driversetup.WaitForDriverReady(5 * time.Second)
-	if err != nil {
-		// todo
-		fmt.Println("could not run chrome: ", err)
-		return 1
+	return driversetup.GetBrowser(name)
+}
+
+// getChromeOptions returns the Chrome options from the Roc Config with the args from the CLI
+func getChromeOptions() (driversetup.ChromeOptions, error) {
+	var chromeOptions driversetup.ChromeOptions
+	if optionsFromUserApp.ChromeOptions != "" {
+		err := json.Unmarshal([]byte(optionsFromUserApp.ChromeOptions), &chromeOptions)
+		if err != nil {
+			return chromeOptions, err
+		}
 	}
 
-	size := C.roc__main_for_host_1_exposed_size()
-	capturePtr := roc_alloc(size, 0)
-	defer roc_dealloc(capturePtr, 0)
+	chromeOptions.Args = append(chromeOptions.Args, options.ChromeArgs...)
 
-	result := C.roc__main_for_host_1_exposed()
+	if options.DriverUrl == "" {
+		// the browser does not run in the current working directory
+		for i, extension := range chromeOptions.Extensions {
+			absPath, err := filepath.Abs(extension)
+			if err != nil {
+				return chromeOptions, err
+			}
+			chromeOptions.Extensions[i] = absPath
+		}
+	}
 
-	// TODO - error handling
-	err = driversetup.HandleCleanup(cmd)
+	return chromeOptions, nil
+}
+
+// the driver is started together with the first session,
+// because the browser is selected in the Roc Config
+var driverCmd *exec.Cmd
+var isDriverReady = false
+
+func ensureDriverRunning(browser driversetup.Browser) error {
+	if isDriverReady {
+		return nil
+	}
+
+	if options.DriverUrl == "" {
+		err := browser.Download()
+		if err != nil {
+			return fmt.Errorf("WebDriverSetupError: %w", err)
+		}
+
+		port, err := driversetup.GetFreePort()
+		if err != nil {
+			return fmt.Errorf("WebDriverSetupError: could not find a free port for the driver: %w", err)
+		}
+
+		cmd, err := browser.RunDriver(port)
+		if err != nil {
+			return fmt.Errorf("WebDriverSetupError: could not run the driver: %w", err)
+		}
+
+		driverCmd = cmd
+		webdriver.SetBaseUrl(fmt.Sprintf("http://localhost:%d", port))
+	}
+
+	err := driversetup.WaitForDriverReady(5 * time.Second)
 	if err != nil {
-		fmt.Println("could not kill chromedriver: ", err)
-		return 1
+		return err
 	}
 
-	return (*(*int)(unsafe.Pointer(&result)))
+	isDriverReady = true
+	return nil
 }
 
 //export roc_fx_set_timeouts
@@ -161,6 +251,82 @@ func roc_fx_set_window_size_override(size *RocStr) {
 	testOverrides.WindowSize = &sizeCopy
 }
 
+//export roc_fx_set_network_conditions
+func roc_fx_set_network_conditions(conditionsJson *RocStr) {
+	// make sure to make a copy of the str - this memory might be realocated
+	bytesCopy := make([]byte, len(conditionsJson.String()))
+	copy(bytesCopy, []byte(conditionsJson.String()))
+	optionsFromUserApp.NetworkConditions = string(bytesCopy)
+}
+
+//export roc_fx_set_network_conditions_override
+func roc_fx_set_network_conditions_override(conditionsJson *RocStr) {
+	// make sure to make a copy of the str - this memory might be realocated
+	bytesCopy := make([]byte, len(conditionsJson.String()))
+	copy(bytesCopy, []byte(conditionsJson.String()))
+	conditionsCopy := string(bytesCopy)
+	testOverrides.NetworkConditions = &conditionsCopy
+}
+
+//export roc_fx_set_device
+func roc_fx_set_device(deviceJson *RocStr) {
+	// make sure to make a copy of the str - this memory might be realocated
+	bytesCopy := make([]byte, len(deviceJson.String()))
+	copy(bytesCopy, []byte(deviceJson.String()))
+	optionsFromUserApp.Device = string(bytesCopy)
+}
+
+//export roc_fx_set_device_override
+func roc_fx_set_device_override(deviceJson *RocStr) {
+	// make sure to make a copy of the str - this memory might be realocated
+	bytesCopy := make([]byte, len(deviceJson.String()))
+	copy(bytesCopy, []byte(deviceJson.String()))
+	deviceCopy := string(bytesCopy)
+	testOverrides.Device = &deviceCopy
+}
+
+//export roc_fx_set_chrome_options
+func roc_fx_set_chrome_options(optionsJson *RocStr) {
+	// make sure to make a copy of the str - this memory might be realocated
+	bytesCopy := make([]byte, len(optionsJson.String()))
+	copy(bytesCopy, []byte(optionsJson.String()))
+	optionsFromUserApp.ChromeOptions = string(bytesCopy)
+}
+
+//export roc_fx_set_locale_override
+func roc_fx_set_locale_override(locale *RocStr) {
+	// make sure to make a copy of the str - this memory might be realocated
+	bytesCopy := make([]byte, len(locale.String()))
+	copy(bytesCopy, []byte(locale.String()))
+	localeCopy := string(bytesCopy)
+	testOverrides.Locale = &localeCopy
+}
+
+//export roc_fx_set_headless_override
+func roc_fx_set_headless_override(headless int64) {
+	headlessBool := headless == 1
+	testOverrides.Headless = &headlessBool
+}
+
+//export roc_fx_set_browser
+func roc_fx_set_browser(name *RocStr) {
+	// make sure to make a copy of the str - this memory might be realocated
+	bytesCopy := make([]byte, len(name.String()))
+	copy(bytesCopy, []byte(name.String()))
+	optionsFromUserApp.Browser = string(bytesCopy)
+}
+
+// where the host writes test artifacts, e.g. printed PDFs
+var resultsDir = "testResults"
+
+//export roc_fx_set_results_dir
+func roc_fx_set_results_dir(dir *RocStr) {
+	// make sure to make a copy of the str - this memory might be realocated
+	bytesCopy := make([]byte, len(dir.String()))
+	copy(bytesCopy, []byte(dir.String()))
+	resultsDir = string(bytesCopy)
+}
+
 //export roc_fx_get_assert_timeout
 func roc_fx_get_assert_timeout() uint64 {
 	assertTimeout := optionsFromUserApp.AssertTimeout
@@ -194,138 +360,880 @@ func roc_fx_get_logs_from_bucket() C.struct_RocList {
 	return createRocListStr(logs)
 }
 
-//export roc_fx_get_test_name_filter
-func roc_fx_get_test_name_filter() C.struct_RocStr {
-	return createRocStr(options.TestNameFilter)
+//export roc_fx_get_test_name_filter
+func roc_fx_get_test_name_filter() C.struct_RocStr {
+	return createRocStr(options.TestNameFilter)
+}
+
+//export roc_fx_get_project_filter
+func roc_fx_get_project_filter() C.struct_RocStr {
+	return createRocStr(options.ProjectFilter)
+}
+
+//export roc_fx_stdout_line
+func roc_fx_stdout_line(msg *RocStr) {
+	fmt.Println(msg)
+	addLogToBucket(msg.String())
+}
+
+//export roc_fx_stdin_line
+func roc_fx_stdin_line() C.struct_RocStr {
+	var input string
+	fmt.Scanln(&input)
+
+	return createRocStr(input)
+}
+
+//export roc_fx_wait
+func roc_fx_wait(timeout int64) {
+	time.Sleep(time.Duration(time.Duration(timeout) * time.Millisecond))
+}
+
+//export roc_fx_start_session
+func roc_fx_start_session() C.struct_ResultVoidStr {
+	browser, err := getBrowser()
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	err = ensureDriverRunning(browser)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	browserSettings := driversetup.BrowserSettings{
+		Headless:   options.Headless,
+		WindowSize: optionsFromUserApp.WindowSize,
+	}
+
+	if options.DriverUrl == "" {
+		browserPath, err := browser.BrowserPath()
+		if err != nil {
+			return createRocResultStr(RocErr, err.Error())
+		}
+
+		browserSettings.BrowserPath = browserPath
+	}
+
+	if testOverrides.WindowSize != nil {
+		browserSettings.WindowSize = *testOverrides.WindowSize
+	}
+
+	if testOverrides.Headless != nil {
+		browserSettings.Headless = *testOverrides.Headless
+	}
+
+	if testOverrides.Locale != nil {
+		browserSettings.Locale = *testOverrides.Locale
+	}
+
+	chromeOptions, err := getChromeOptions()
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	browserSettings.ChromeOptions = chromeOptions
+
+	if options.DriverUrl == "" {
+		// the remote browsers save the downloads on their own machine
+		downloadDir, err := getSessionDownloadDir(browser, chromeOptions)
+		if err != nil {
+			return createRocResultStr(RocErr, err.Error())
+		}
+
+		browserSettings.DownloadDir = downloadDir
+	}
+
+	deviceJson := optionsFromUserApp.Device
+	if testOverrides.Device != nil {
+		deviceJson = *testOverrides.Device
+	}
+
+	if deviceJson != "" {
+		var mobileEmulation webdriver.MobileEmulation
+		err = json.Unmarshal([]byte(deviceJson), &mobileEmulation)
+		if err != nil {
+			return createRocResultStr(RocErr, err.Error())
+		}
+
+		browserSettings.MobileEmulation = &mobileEmulation
+	}
+
+	serverOptions := webdriver.SessionOptions{
+		BrowserName:     browser.Name(),
+		ImplicitTimeout: optionsFromUserApp.ElementImplicitTimeout,
+		PageLoadTimeout: optionsFromUserApp.PageLoadTimeout,
+		ScriptTimeout:   optionsFromUserApp.ScriptExecutionTimeout,
+	}
+
+	if testOverrides.ElementImplicitTimeout != nil {
+		serverOptions.ImplicitTimeout = *testOverrides.ElementImplicitTimeout
+	}
+
+	if testOverrides.PageLoadTimeout != nil {
+		serverOptions.PageLoadTimeout = *testOverrides.PageLoadTimeout
+	}
+
+	if testOverrides.ScriptExecutionTimeout != nil {
+		serverOptions.ScriptTimeout = *testOverrides.ScriptExecutionTimeout
+	}
+
+	serverOptions.BrowserCapabilities = browser.Capabilities(browserSettings)
+
+	sessionId, capabilities, err := webdriver.CreateSession(serverOptions)
+
+	if err != nil {
+		if browserSettings.DownloadDir != "" {
+			os.Remove(browserSettings.DownloadDir)
+		}
+		return createRocResultStr(RocErr, err.Error())
+	} else {
+		sessionCapabilities[sessionId] = capabilities
+		if browserSettings.DownloadDir != "" {
+			downloadDirs[sessionId] = browserSettings.DownloadDir
+		}
+
+		conditionsJson := optionsFromUserApp.NetworkConditions
+		if testOverrides.NetworkConditions != nil {
+			conditionsJson = *testOverrides.NetworkConditions
+		}
+
+		// the network conditions are a chromedriver extension, Firefox runs without them
+		if browser.Name() == "chrome" {
+			err = applyNetworkConditions(sessionId, conditionsJson, false)
+			if err != nil {
+				webdriver.DeleteSession(sessionId)
+				closeDevTools(sessionId)
+				return createRocResultStr(RocErr, err.Error())
+			}
+		}
+
+		// the requests are recorded from the start of the session - Firefox and remote browsers
+		// have no DevTools connection, the Network functions return the error instead
+		if options.DriverUrl == "" && browser.Name() == "chrome" {
+			_, err = getNetworkRecorder(sessionId)
+			if err != nil {
+				// only the tests using the recorded requests should fail
+				networkRecorderErrors[sessionId] = err
+			}
+		}
+
+		return createRocResultStr(RocOk, sessionId)
+	}
+}
+
+// applyNetworkConditions sets the JSON webdriver.NetworkConditions - a new session is not throttled,
+// so the conditions without throttling are only sent when they replace other conditions
+func applyNetworkConditions(sessionId, conditionsJson string, replace bool) error {
+	if conditionsJson == "" {
+		return nil
+	}
+
+	var conditions webdriver.NetworkConditions
+	err := json.Unmarshal([]byte(conditionsJson), &conditions)
+	if err != nil {
+		return err
+	}
+
+	if !replace && !conditions.IsThrottled() {
+		return nil
+	}
+
+	return webdriver.SetNetworkConditions(sessionId, conditions)
+}
+
+//export roc_fx_browser_set_network_conditions
+func roc_fx_browser_set_network_conditions(sessionId, conditionsJson *RocStr) C.struct_ResultVoidStr {
+	browser, err := getBrowser()
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	if browser.Name() != "chrome" {
+		return createRocResultStr(RocErr, "network conditions are not supported in Firefox")
+	}
+
+	err = applyNetworkConditions(sessionId.String(), conditionsJson.String(), true)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	} else {
+		return createRocResultStr(RocOk, "")
+	}
+}
+
+//export roc_fx_delete_session
+func roc_fx_delete_session(sessionId *RocStr) C.struct_ResultVoidStr {
+	delete(frameStacks, sessionId.String())
+	delete(consoleLogs, sessionId.String())
+	delete(downloadDirs, sessionId.String())
+	closeDevTools(sessionId.String())
+
+	err := webdriver.DeleteSession(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	} else {
+		return createRocResultStr(RocOk, "")
+	}
+}
+
+// timeouts are passed to Roc as [implicit, pageLoad, script] - -1 stands for a null (unlimited) timeout
+
+//export roc_fx_browser_get_timeouts
+func roc_fx_browser_get_timeouts(sessionId *RocStr) C.struct_ResultListStr {
+	timeouts, err := webdriver.GetTimeouts(sessionId.String())
+	if err != nil {
+		return createRocResult_ListI64_Str(RocErr, nil, err.Error())
+	}
+
+	toI64 := func(timeout *uint64) int64 {
+		if timeout == nil {
+			return -1
+		}
+
+		return int64(*timeout)
+	}
+
+	timeoutList := []int64{toI64(timeouts.Implicit), toI64(timeouts.PageLoad), toI64(timeouts.Script)}
+	return createRocResult_ListI64_Str(RocOk, timeoutList, "")
+}
+
+//export roc_fx_browser_set_timeouts
+func roc_fx_browser_set_timeouts(sessionId *RocStr, implicitTimeout, pageLoadTimeout, scriptTimeout int64) C.struct_ResultVoidStr {
+	fromI64 := func(timeout int64) *uint64 {
+		if timeout < 0 {
+			return nil
+		}
+
+		value := uint64(timeout)
+		return &value
+	}
+
+	timeouts := webdriver.Timeouts{
+		Implicit: fromI64(implicitTimeout),
+		PageLoad: fromI64(pageLoadTimeout),
+		Script:   fromI64(scriptTimeout),
+	}
+
+	err := webdriver.SetTimeouts(sessionId.String(), timeouts)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, "")
+}
+
+// consoleLogs keeps the console logs of each session - the driver returns every entry only once
+var consoleLogs = make(map[string][]webdriver.LogEntry)
+
+//export roc_fx_browser_get_console_logs
+func roc_fx_browser_get_console_logs(sessionId *RocStr) C.struct_ResultVoidStr {
+	logs, err := collectConsoleLogs(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	if logs == nil {
+		logs = []webdriver.LogEntry{}
+	}
+
+	jsonData, err := json.Marshal(logs)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, string(jsonData))
+}
+
+// js errors are the uncaught exceptions and console.error messages of the session,
+// without the ones matching any of the allow-list regex patterns (passed as a JSON array)
+
+// jsErrorsWarningShown is set after the fail_on_js_errors warning for Firefox was printed - it is printed once per run
+var jsErrorsWarningShown = false
+
+//export roc_fx_browser_get_js_errors
+func roc_fx_browser_get_js_errors(sessionId, allowListJson *RocStr) C.struct_ResultListStr {
+	browser, err := getBrowser()
+	if err != nil {
+		return createRocResult_ListStr_Str(RocErr, nil, err.Error())
+	}
+
+	// Firefox has no console logs - the check is skipped instead of failing every test
+	if browser.Name() != "chrome" {
+		if !jsErrorsWarningShown {
+			fmt.Println(utils.FG_YELLOW + "fail_on_js_errors is not supported in Firefox - the JavaScript errors are not checked" + utils.RESET)
+			jsErrorsWarningShown = true
+		}
+
+		return createRocResult_ListStr_Str(RocOk, []string{}, "")
+	}
+
+	var allowList []string
+	err = json.Unmarshal([]byte(allowListJson.String()), &allowList)
+	if err != nil {
+		return createRocResult_ListStr_Str(RocErr, nil, err.Error())
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(allowList))
+	for _, pattern := range allowList {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return createRocResult_ListStr_Str(RocErr, nil, fmt.Sprintf("invalid js errors allow-list pattern \"%s\": %s", pattern, err))
+		}
+
+		patterns = append(patterns, compiled)
+	}
+
+	logs, err := collectConsoleLogs(sessionId.String())
+	if err != nil {
+		return createRocResult_ListStr_Str(RocErr, nil, err.Error())
+	}
+
+	jsErrors := make([]string, 0)
+	for _, log := range logs {
+		isJsError := log.Level == "SEVERE" && (log.Source == "javascript" || log.Source == "console-api")
+		if isJsError && !matchesAny(patterns, log.Message) {
+			jsErrors = append(jsErrors, log.Message)
+		}
+	}
+
+	return createRocResult_ListStr_Str(RocOk, jsErrors, "")
+}
+
+// collectConsoleLogs adds the new logs from the driver to the logs of the session and returns all of them
+func collectConsoleLogs(sessionId string) ([]webdriver.LogEntry, error) {
+	browser, err := getBrowser()
+	if err != nil {
+		return nil, err
+	}
+
+	newLogs, err := browser.ConsoleLogs(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	consoleLogs[sessionId] = append(consoleLogs[sessionId], newLogs...)
+
+	return consoleLogs[sessionId], nil
+}
+
+func matchesAny(patterns []*regexp.Regexp, str string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(str) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sessionCapabilities keeps the capabilities returned by the driver for each session
+var sessionCapabilities = make(map[string]map[string]interface{})
+
+// devToolsConnections keeps the Chrome DevTools Protocol connection of each session,
+// opened on first use to the window that is current at that moment
+var devToolsConnections = make(map[string]*webdriver.CdpConnection)
+
+var networkInterceptors = make(map[string]*webdriver.NetworkInterceptor)
+
+// networkRecorders keep the requests of each session, started together with the session
+// so that the requests made before the first Roc call are recorded too
+var networkRecorders = make(map[string]*webdriver.NetworkRecorder)
+
+// networkRecorderErrors keep why the recorder could not be started with the session
+var networkRecorderErrors = make(map[string]error)
+
+func getDevTools(sessionId string) (*webdriver.CdpConnection, error) {
+	connection, ok := devToolsConnections[sessionId]
+	if ok {
+		return connection, nil
+	}
+
+	if options.DriverUrl != "" {
+		// the debuggerAddress of a remote browser is on the localhost of the grid node
+		return nil, fmt.Errorf("the Chrome DevTools Protocol is not supported with a remote driver (--driver-url)")
+	}
+
+	browser, err := getBrowser()
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := browser.DevToolsAddress(sessionCapabilities[sessionId])
+	if err != nil {
+		return nil, err
+	}
+
+	windowHandle, err := webdriver.GetWindowHandle(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	connection, err = webdriver.OpenCdpConnection(address, windowHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	devToolsConnections[sessionId] = connection
+	return connection, nil
+}
+
+func getNetworkInterceptor(sessionId string) (*webdriver.NetworkInterceptor, error) {
+	interceptor, ok := networkInterceptors[sessionId]
+	if ok {
+		return interceptor, nil
+	}
+
+	connection, err := getDevTools(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	interceptor = webdriver.NewNetworkInterceptor(connection)
+	networkInterceptors[sessionId] = interceptor
+	return interceptor, nil
+}
+
+func getNetworkRecorder(sessionId string) (*webdriver.NetworkRecorder, error) {
+	recorder, ok := networkRecorders[sessionId]
+	if ok {
+		return recorder, nil
+	}
+
+	recorderErr, ok := networkRecorderErrors[sessionId]
+	if ok {
+		return nil, fmt.Errorf("could not record the network requests: %w", recorderErr)
+	}
+
+	connection, err := getDevTools(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder, err = webdriver.NewNetworkRecorder(connection)
+	if err != nil {
+		return nil, err
+	}
+
+	networkRecorders[sessionId] = recorder
+	return recorder, nil
+}
+
+func closeDevTools(sessionId string) {
+	connection, ok := devToolsConnections[sessionId]
+	if ok {
+		connection.Close()
+	}
+
+	delete(devToolsConnections, sessionId)
+	delete(networkInterceptors, sessionId)
+	delete(networkRecorders, sessionId)
+	delete(networkRecorderErrors, sessionId)
+	delete(sessionCapabilities, sessionId)
+}
+
+// routes are passed from Roc as a JSON webdriver.Route
+
+//export roc_fx_network_add_route
+func roc_fx_network_add_route(sessionId, routeJson *RocStr) C.struct_ResultVoidStr {
+	var route webdriver.Route
+	err := json.Unmarshal([]byte(routeJson.String()), &route)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	interceptor, err := getNetworkInterceptor(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	err = interceptor.AddRoute(route)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	} else {
+		return createRocResultStr(RocOk, "")
+	}
+}
+
+//export roc_fx_network_get_requests
+func roc_fx_network_get_requests(sessionId *RocStr) C.struct_ResultVoidStr {
+	recorder, err := getNetworkRecorder(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	requestsJson, err := json.Marshal(recorder.Requests())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, string(requestsJson))
+}
+
+// returns the response as JSON, or an empty string when there is no matching response yet
+
+//export roc_fx_network_take_response
+func roc_fx_network_take_response(sessionId, urlPattern *RocStr) C.struct_ResultVoidStr {
+	recorder, err := getNetworkRecorder(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	response, err := recorder.TakeResponse(urlPattern.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	if response == nil {
+		return createRocResultStr(RocOk, "")
+	}
+
+	responseJson, err := json.Marshal(response)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, string(responseJson))
+}
+
+// the network idle and DOM stable waits use the page load timeout of the session
+
+//export roc_fx_browser_wait_for_network_idle
+func roc_fx_browser_wait_for_network_idle(sessionId *RocStr, idleTime int64) C.struct_ResultVoidStr {
+	recorder, err := getNetworkRecorder(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	timeout, err := getPageLoadTimeout(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	err = recorder.WaitForIdle(time.Duration(idleTime)*time.Millisecond, timeout)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	} else {
+		return createRocResultStr(RocOk, "")
+	}
+}
+
+//export roc_fx_browser_wait_for_dom_stable
+func roc_fx_browser_wait_for_dom_stable(sessionId *RocStr, quietTime int64) C.struct_ResultVoidStr {
+	timeout, err := getPageLoadTimeout(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	err = webdriver.WaitForDomStable(sessionId.String(), time.Duration(quietTime)*time.Millisecond, timeout)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	} else {
+		return createRocResultStr(RocOk, "")
+	}
+}
+
+func getPageLoadTimeout(sessionId string) (time.Duration, error) {
+	timeouts, err := webdriver.GetTimeouts(sessionId)
+	if err != nil {
+		return 0, err
+	}
+
+	if timeouts.PageLoad == nil {
+		return time.Duration(optionsFromUserApp.PageLoadTimeout) * time.Millisecond, nil
+	}
+
+	return time.Duration(*timeouts.PageLoad) * time.Millisecond, nil
+}
+
+// downloadDirs keeps the download directory of each session - the files are kept after the session ends
+var downloadDirs = make(map[string]string)
+
+// createDownloadDir creates an empty directory for the downloads of a new session in the results dir
+func createDownloadDir() (string, error) {
+	downloadsRoot := filepath.Join(resultsDir, "downloads")
+	err := os.MkdirAll(downloadsRoot, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	downloadDir, err := os.MkdirTemp(downloadsRoot, "session-")
+	if err != nil {
+		return "", err
+	}
+
+	// the browser does not run in the current working directory
+	return filepath.Abs(downloadDir)
+}
+
+// getSessionDownloadDir returns the "download.default_directory" Chrome pref when it is set in the Roc Config
+// (shared by all sessions), or creates a new directory for the session
+func getSessionDownloadDir(browser driversetup.Browser, chromeOptions driversetup.ChromeOptions) (string, error) {
+	prefDir, ok := chromeOptions.Prefs["download.default_directory"]
+	if !ok || browser.Name() != "chrome" {
+		return createDownloadDir()
+	}
+
+	prefDirStr, ok := prefDir.(string)
+	if !ok || prefDirStr == "" {
+		return "", fmt.Errorf("the \"download.default_directory\" Chrome pref has to be a path, but got: %v", prefDir)
+	}
+
+	err := os.MkdirAll(prefDirStr, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Abs(prefDirStr)
+}
+
+func getDownloadDir(sessionId string) (string, error) {
+	downloadDir, ok := downloadDirs[sessionId]
+	if !ok {
+		return "", fmt.Errorf("downloads are only supported with a local browser - the download directory of a remote browser is not accessible")
+	}
+
+	return downloadDir, nil
+}
+
+// returns the path of the downloaded file - the timeout of -1 waits for the page load timeout
+
+//export roc_fx_browser_wait_for_download
+func roc_fx_browser_wait_for_download(sessionId, namePattern *RocStr, timeoutMs int64) C.struct_ResultVoidStr {
+	downloadDir, err := getDownloadDir(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	timeout := time.Duration(timeoutMs) * time.Millisecond
+	if timeoutMs == -1 {
+		timeout, err = getPageLoadTimeout(sessionId.String())
+		if err != nil {
+			return createRocResultStr(RocErr, err.Error())
+		}
+	}
+
+	fileName, err := webdriver.WaitForDownload(downloadDir, namePattern.String(), timeout)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, filepath.Join(downloadDir, fileName))
+}
+
+//export roc_fx_browser_get_downloads
+func roc_fx_browser_get_downloads(sessionId *RocStr) C.struct_ResultListStr {
+	downloadDir, err := getDownloadDir(sessionId.String())
+	if err != nil {
+		return createRocResult_ListStr_Str(RocErr, nil, err.Error())
+	}
+
+	files, err := webdriver.ListDownloads(downloadDir)
+	if err != nil {
+		return createRocResult_ListStr_Str(RocErr, nil, err.Error())
+	}
+
+	return createRocResult_ListStr_Str(RocOk, files, "")
+}
+
+//export roc_fx_browser_read_download
+func roc_fx_browser_read_download(sessionId, fileName *RocStr) C.struct_ResultListStr {
+	downloadDir, err := getDownloadDir(sessionId.String())
+	if err != nil {
+		return createRocResult_ListU8_Str(RocErr, nil, err.Error())
+	}
+
+	content, err := webdriver.ReadDownload(downloadDir, fileName.String())
+	if err != nil {
+		return createRocResult_ListU8_Str(RocErr, nil, err.Error())
+	}
+
+	return createRocResult_ListU8_Str(RocOk, content, "")
+}
+
+//export roc_fx_network_clear_routes
+func roc_fx_network_clear_routes(sessionId *RocStr) C.struct_ResultVoidStr {
+	interceptor, ok := networkInterceptors[sessionId.String()]
+	if !ok {
+		return createRocResultStr(RocOk, "")
+	}
+
+	err := interceptor.ClearRoutes()
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	} else {
+		return createRocResultStr(RocOk, "")
+	}
+}
+
+//export roc_fx_browser_get_screenshot
+func roc_fx_browser_get_screenshot(sessionId *RocStr) C.struct_ResultVoidStr {
+	screenshotBase64, err := webdriver.BrowserGetScreenshot(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	} else {
+		return createRocResultStr(RocOk, screenshotBase64)
+	}
+}
+
+//export roc_fx_element_get_screenshot
+func roc_fx_element_get_screenshot(sessionId, elementId *RocStr) C.struct_ResultVoidStr {
+	screenshotBase64, err := webdriver.ElementGetScreenshot(sessionId.String(), elementId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	} else {
+		return createRocResultStr(RocOk, screenshotBase64)
+	}
+}
+
+//export roc_fx_execute_js
+func roc_fx_execute_js(sessionId, jsString, argsStr *RocStr) C.struct_ResultVoidStr {
+	result, err := webdriver.ExecuteJs(sessionId.String(), jsString.String(), argsStr.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	} else {
+		return createRocResultStr(RocOk, result)
+	}
 }
 
-//export roc_fx_stdout_line
-func roc_fx_stdout_line(msg *RocStr) {
-	fmt.Println(msg)
-	addLogToBucket(msg.String())
+//export roc_fx_execute_js_json
+func roc_fx_execute_js_json(sessionId, jsString, argsStr *RocStr) C.struct_ResultVoidStr {
+	result, err := webdriver.ExecuteJsJson(sessionId.String(), jsString.String(), argsStr.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, result)
 }
 
-//export roc_fx_stdin_line
-func roc_fx_stdin_line() C.struct_RocStr {
-	var input string
-	fmt.Scanln(&input)
+//export roc_fx_execute_js_elements
+func roc_fx_execute_js_elements(sessionId, jsString, argsStr *RocStr) C.struct_ResultListStr {
+	elementIds, err := webdriver.ExecuteJsElements(sessionId.String(), jsString.String(), argsStr.String())
+	if err != nil {
+		return createRocResult_ListStr_Str(RocErr, nil, err.Error())
+	}
 
-	return createRocStr(input)
+	return createRocResult_ListStr_Str(RocOk, elementIds, "")
 }
 
-//export roc_fx_wait
-func roc_fx_wait(timeout int64) {
-	time.Sleep(time.Duration(time.Duration(timeout) * time.Millisecond))
+//export roc_fx_execute_js_async
+func roc_fx_execute_js_async(sessionId, jsString, argsStr *RocStr) C.struct_ResultVoidStr {
+	result, err := webdriver.ExecuteJsAsync(sessionId.String(), jsString.String(), argsStr.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	} else {
+		return createRocResultStr(RocOk, result)
+	}
 }
 
-//export roc_fx_start_session
-func roc_fx_start_session() C.struct_ResultVoidStr {
-	serverOptions := webdriver.SessionOptions{
-		Headless:        options.Headless,
-		WindowSize:      optionsFromUserApp.WindowSize,
-		ImplicitTimeout: optionsFromUserApp.ElementImplicitTimeout,
-		PageLoadTimeout: optionsFromUserApp.PageLoadTimeout,
-		ScriptTimeout:   optionsFromUserApp.ScriptExecutionTimeout,
+//export roc_fx_browser_print_pdf
+func roc_fx_browser_print_pdf(sessionId, fileName *RocStr, width, height, top, bottom, left, right, scale float64, orientationStr *RocStr, shrinkToFit, background int64, pageRanges *RocStr) C.struct_ResultVoidStr {
+	// the pdf files are only saved directly in the "pdf" results directory
+	if fileName.String() != filepath.Base(fileName.String()) {
+		return createRocResultStr(RocErr, fmt.Sprintf("invalid pdf file name: %s", fileName.String()))
 	}
 
-	if testOverrides.WindowSize != nil {
-		serverOptions.WindowSize = *testOverrides.WindowSize
+	pageRangesArr := []string{}
+	if pageRanges.String() != "" {
+		pageRangesArr = strings.Split(pageRanges.String(), ",")
 	}
 
-	if testOverrides.ElementImplicitTimeout != nil {
-		serverOptions.ImplicitTimeout = *testOverrides.ElementImplicitTimeout
+	pdfOptions := webdriver.PdfOptions{
+		Page: webdriver.PdfPageOptions{
+			Width:  width,
+			Height: height,
+		},
+		Margin: webdriver.PdfMarginOptions{
+			Top:    top,
+			Bottom: bottom,
+			Left:   left,
+			Right:  right,
+		},
+		Scale:       scale,
+		Orientation: orientationStr.String(),
+		ShrinkToFit: shrinkToFit == 1,
+		Background:  background == 1,
+		PageRanges:  pageRangesArr,
 	}
 
-	if testOverrides.PageLoadTimeout != nil {
-		serverOptions.PageLoadTimeout = *testOverrides.PageLoadTimeout
+	pdfBase64, err := webdriver.BrowserGetPdf(sessionId.String(), pdfOptions)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
 	}
 
-	if testOverrides.ScriptExecutionTimeout != nil {
-		serverOptions.ScriptTimeout = *testOverrides.ScriptExecutionTimeout
+	pdf, err := base64.StdEncoding.DecodeString(pdfBase64)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
 	}
 
-	sessionId, err := webdriver.CreateSession(serverOptions)
+	pdfPath := filepath.Join(resultsDir, "pdf", fileName.String())
+	err = os.MkdirAll(filepath.Dir(pdfPath), os.ModePerm)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
 
+	err = os.WriteFile(pdfPath, pdf, 0644)
 	if err != nil {
 		return createRocResultStr(RocErr, err.Error())
-	} else {
-		return createRocResultStr(RocOk, sessionId)
 	}
+
+	return createRocResultStr(RocOk, pdfPath)
 }
 
-//export roc_fx_delete_session
-func roc_fx_delete_session(sessionId *RocStr) C.struct_ResultVoidStr {
-	err := webdriver.DeleteSession(sessionId.String())
+//export roc_fx_browser_get_window_handle
+func roc_fx_browser_get_window_handle(sessionId *RocStr) C.struct_ResultVoidStr {
+	handle, err := webdriver.GetWindowHandle(sessionId.String())
 	if err != nil {
 		return createRocResultStr(RocErr, err.Error())
-	} else {
-		return createRocResultStr(RocOk, "")
 	}
+
+	return createRocResultStr(RocOk, handle)
 }
 
-//export roc_fx_browser_get_screenshot
-func roc_fx_browser_get_screenshot(sessionId *RocStr) C.struct_ResultVoidStr {
-	screenshotBase64, err := webdriver.BrowserGetScreenshot(sessionId.String())
+//export roc_fx_browser_get_window_handles
+func roc_fx_browser_get_window_handles(sessionId *RocStr) C.struct_ResultListStr {
+	handles, err := webdriver.GetWindowHandles(sessionId.String())
+	if err != nil {
+		return createRocResult_ListStr_Str(RocErr, nil, err.Error())
+	}
+
+	return createRocResult_ListStr_Str(RocOk, handles, "")
+}
+
+//export roc_fx_browser_new_window
+func roc_fx_browser_new_window(sessionId, typeHint *RocStr) C.struct_ResultVoidStr {
+	handle, err := webdriver.NewWindow(sessionId.String(), typeHint.String())
 	if err != nil {
 		return createRocResultStr(RocErr, err.Error())
-	} else {
-		return createRocResultStr(RocOk, screenshotBase64)
 	}
+
+	return createRocResultStr(RocOk, handle)
 }
 
-//export roc_fx_execute_js
-func roc_fx_execute_js(sessionId, jsString, argsStr *RocStr) C.struct_ResultVoidStr {
-	result, err := webdriver.ExecuteJs(sessionId.String(), jsString.String(), argsStr.String())
+//export roc_fx_browser_switch_to_window
+func roc_fx_browser_switch_to_window(sessionId, handle *RocStr) C.struct_ResultVoidStr {
+	err := webdriver.SwitchToWindow(sessionId.String(), handle.String())
 	if err != nil {
 		return createRocResultStr(RocErr, err.Error())
-	} else {
-		return createRocResultStr(RocOk, result)
 	}
+
+	// switching windows always lands in the top-level context of the new window
+	delete(frameStacks, sessionId.String())
+
+	return createRocResultStr(RocOk, "")
 }
 
-// //export roc_fx_browserGetPdf
-// func roc_fx_browserGetPdf(sessionId *RocStr, width, height, top, bottom, left, right, scale float64, orientationStr *RocStr, shrinkToFit, background int64, pageRanges *RocList[RocStr]) C.struct_ResultVoidStr {
-// 	shrinkToFitBool := false
-// 	if shrinkToFit == 1 {
-// 		shrinkToFitBool = true
-// 	}
-//
-// 	backgroundBool := false
-// 	if background == 1 {
-// 		backgroundBool = true
-// 	}
-//
-// 	goSlice := pageRanges.List()
-// 	pageRangesArr := make([]string, len(goSlice))
-// 	for i, rocStr := range goSlice {
-// 		pageRangesArr[i] = rocStr.String()
-// 	}
-//
-// 	pdfOptions := webdriver.PdfOptions{
-// 		Page: webdriver.PdfPageOptions{
-// 			Width:  width,
-// 			Height: height,
-// 		},
-// 		Margin: webdriver.PdfMarginOptions{
-// 			Top:    top,
-// 			Bottom: bottom,
-// 			Left:   left,
-// 			Right:  right,
-// 		},
-// 		Scale:       scale,
-// 		Orientation: orientationStr.String(),
-// 		ShrinkToFit: shrinkToFitBool,
-// 		Background:  backgroundBool,
-// 		PageRages:   pageRangesArr,
-// 	}
-//
-// 	screenshotBase64, err := webdriver.BrowserGetPdf(sessionId.String(), pdfOptions)
-// 	if err != nil {
-// 		return createRocResultStr(RocErr, err.Error())
-// 	} else {
-// 		return createRocResultStr(RocOk, screenshotBase64)
-// 	}
-// }
+//export roc_fx_browser_close_current_window
+func roc_fx_browser_close_current_window(sessionId *RocStr) C.struct_ResultListStr {
+	handles, err := webdriver.CloseWindow(sessionId.String())
+	if err != nil {
+		return createRocResult_ListStr_Str(RocErr, nil, err.Error())
+	}
+
+	// the frames of the closed window are gone
+	delete(frameStacks, sessionId.String())
+
+	return createRocResult_ListStr_Str(RocOk, handles, "")
+}
 
 //export roc_fx_browser_set_window_rect
 func roc_fx_browser_set_window_rect(sessionId *RocStr, disciminant, x, y, width, height int64) C.struct_ResultListStr {
@@ -420,6 +1328,9 @@ func roc_fx_browser_navigate_back(sessionId *RocStr) C.struct_ResultVoidStr {
 		return createRocResultStr(RocErr, err.Error())
 	}
 
+	// going back lands in the top-level context
+	delete(frameStacks, sessionId.String())
+
 	return createRocResultStr(RocOk, "")
 }
 
@@ -430,6 +1341,9 @@ func roc_fx_browser_navigate_forward(sessionId *RocStr) C.struct_ResultVoidStr {
 		return createRocResultStr(RocErr, err.Error())
 	}
 
+	// going forward lands in the top-level context
+	delete(frameStacks, sessionId.String())
+
 	return createRocResultStr(RocOk, "")
 }
 
@@ -440,6 +1354,9 @@ func roc_fx_browser_reload(sessionId *RocStr) C.struct_ResultVoidStr {
 		return createRocResultStr(RocErr, err.Error())
 	}
 
+	// reloading lands in the top-level context
+	delete(frameStacks, sessionId.String())
+
 	return createRocResultStr(RocOk, "")
 }
 
@@ -450,16 +1367,58 @@ func roc_fx_browser_navigate_to(sessionId, url *RocStr) C.struct_ResultVoidStr {
 		return createRocResultStr(RocErr, err.Error())
 	}
 
+	// navigation always lands in the top-level context
+	delete(frameStacks, sessionId.String())
+
 	return createRocResultStr(RocOk, "")
 }
 
+// frameStacks keeps the frames entered in each session, from the top-level browsing context down.
+// It lets Roc restore the exact previous frame context, even when a callback failed somewhere deeper.
+var frameStacks = make(map[string][]frameReference)
+
+type frameReference struct {
+	elementId string
+	index     int64
+	byIndex   bool
+	topLevel  bool
+}
+
+func (frame frameReference) switchTo(sessionId string) error {
+	if frame.topLevel {
+		return webdriver.SwitchToTopLevelFrame(sessionId)
+	}
+
+	if frame.byIndex {
+		return webdriver.SwitchToFrameByIndex(sessionId, frame.index)
+	}
+
+	return webdriver.SwitchToFrameByElementId(sessionId, frame.elementId)
+}
+
 //export roc_fx_switch_to_frame_by_element_id
 func roc_fx_switch_to_frame_by_element_id(sessionId, elementId *RocStr) C.struct_ResultVoidStr {
-	err := webdriver.SwitchToFrameByElementId(sessionId.String(), elementId.String())
+	frame := frameReference{elementId: elementId.String()}
+	err := frame.switchTo(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	frameStacks[sessionId.String()] = append(frameStacks[sessionId.String()], frame)
+
+	return createRocResultStr(RocOk, "")
+}
+
+//export roc_fx_switch_to_frame_by_index
+func roc_fx_switch_to_frame_by_index(sessionId *RocStr, index int64) C.struct_ResultVoidStr {
+	frame := frameReference{index: index, byIndex: true}
+	err := frame.switchTo(sessionId.String())
 	if err != nil {
 		return createRocResultStr(RocErr, err.Error())
 	}
 
+	frameStacks[sessionId.String()] = append(frameStacks[sessionId.String()], frame)
+
 	return createRocResultStr(RocOk, "")
 }
 
@@ -470,9 +1429,79 @@ func roc_fx_switch_to_parent_frame(sessionId *RocStr) C.struct_ResultVoidStr {
 		return createRocResultStr(RocErr, err.Error())
 	}
 
+	stack := frameStacks[sessionId.String()]
+	if len(stack) > 0 {
+		frameStacks[sessionId.String()] = stack[:len(stack)-1]
+	}
+
+	return createRocResultStr(RocOk, "")
+}
+
+//export roc_fx_switch_to_top_level_frame
+func roc_fx_switch_to_top_level_frame(sessionId *RocStr) C.struct_ResultVoidStr {
+	// the top-level switch is pushed as a frame too, so the frames left behind can still be restored
+	frame := frameReference{topLevel: true}
+	err := frame.switchTo(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	frameStacks[sessionId.String()] = append(frameStacks[sessionId.String()], frame)
+
+	return createRocResultStr(RocOk, "")
+}
+
+//export roc_fx_get_frame_depth
+func roc_fx_get_frame_depth(sessionId *RocStr) uint64 {
+	return uint64(len(frameStacks[sessionId.String()]))
+}
+
+//export roc_fx_restore_frame_depth
+func roc_fx_restore_frame_depth(sessionId *RocStr, depth uint64) C.struct_ResultVoidStr {
+	err := restoreFrameDepth(sessionId.String(), int(depth))
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
 	return createRocResultStr(RocOk, "")
 }
 
+// restoreFrameDepth brings the session back to the frame it was in when the stack had the given depth.
+// One level up is a plain parent switch, anything else is replayed from the top-level context.
+func restoreFrameDepth(sessionId string, depth int) error {
+	stack := frameStacks[sessionId]
+	if depth >= len(stack) {
+		return nil
+	}
+
+	if depth == len(stack)-1 && !stack[depth].topLevel {
+		err := webdriver.SwitchToParenFrame(sessionId)
+		if err != nil {
+			return err
+		}
+
+		frameStacks[sessionId] = stack[:depth]
+		return nil
+	}
+
+	err := webdriver.SwitchToTopLevelFrame(sessionId)
+	if err != nil {
+		return err
+	}
+
+	frameStacks[sessionId] = nil
+	for _, frame := range stack[:depth] {
+		err := frame.switchTo(sessionId)
+		if err != nil {
+			return err
+		}
+
+		frameStacks[sessionId] = append(frameStacks[sessionId], frame)
+	}
+
+	return nil
+}
+
 //export roc_fx_alert_accept
 func roc_fx_alert_accept(sessionId *RocStr) C.struct_ResultVoidStr {
 	err := webdriver.AlertAccept(sessionId.String())
@@ -516,9 +1545,6 @@ func roc_fx_alert_send_text(sessionId, text *RocStr) C.struct_ResultVoidStr {
 //export roc_fx_browser_find_element
 func roc_fx_browser_find_element(sessionId, using, value *RocStr) C.struct_ResultVoidStr {
 	elementId, err := webdriver.FindElement(sessionId.String(), using.String(), value.String())
-	// if notFoundError, ok := err.(*webdriver.WebDriverNotFoundError); ok {
-	//    return createRocResultStr(RocErr, fmt.Sprintf("WebDriverNotFoundError::"))
-	// }
 	if err != nil {
 		return createRocResultStr(RocErr, err.Error())
 	}
@@ -556,6 +1582,36 @@ func roc_fx_element_find_elements(sessionId, parentElementId, using, value *RocS
 	return createRocResult_ListStr_Str(RocOk, elementIds, "")
 }
 
+//export roc_fx_element_get_shadow_root
+func roc_fx_element_get_shadow_root(sessionId, elementId *RocStr) C.struct_ResultVoidStr {
+	shadowRootId, err := webdriver.GetShadowRoot(sessionId.String(), elementId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, shadowRootId)
+}
+
+//export roc_fx_shadow_root_find_element
+func roc_fx_shadow_root_find_element(sessionId, shadowRootId, using, value *RocStr) C.struct_ResultVoidStr {
+	elementId, err := webdriver.FindElementInShadowRoot(sessionId.String(), shadowRootId.String(), using.String(), value.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, elementId)
+}
+
+//export roc_fx_shadow_root_find_elements
+func roc_fx_shadow_root_find_elements(sessionId, shadowRootId, using, value *RocStr) C.struct_ResultListStr {
+	elementIds, err := webdriver.FindElementsInShadowRoot(sessionId.String(), shadowRootId.String(), using.String(), value.String())
+	if err != nil {
+		return createRocResult_ListStr_Str(RocErr, nil, err.Error())
+	}
+
+	return createRocResult_ListStr_Str(RocOk, elementIds, "")
+}
+
 //export roc_fx_browser_get_title
 func roc_fx_browser_get_title(sessionId *RocStr) C.struct_ResultVoidStr {
 	title, err := webdriver.GetBrowserTitle(sessionId.String())
@@ -703,6 +1759,32 @@ func roc_fx_element_send_keys(sessionId, elementId, text *RocStr) C.struct_Resul
 	return createRocResultStr(RocOk, "")
 }
 
+//export roc_fx_perform_actions
+func roc_fx_perform_actions(sessionId, actionsJson *RocStr) C.struct_ResultVoidStr {
+	var actions []webdriver.ActionSequence
+	err := json.Unmarshal([]byte(actionsJson.String()), &actions)
+	if err != nil {
+		return createRocResultStr(RocErr, fmt.Sprintf("invalid actions: %s", err))
+	}
+
+	err = webdriver.PerformActions(sessionId.String(), actions)
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, "")
+}
+
+//export roc_fx_release_actions
+func roc_fx_release_actions(sessionId *RocStr) C.struct_ResultVoidStr {
+	err := webdriver.ReleaseActions(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, "")
+}
+
 //export roc_fx_element_clear
 func roc_fx_element_clear(sessionId, elementId *RocStr) C.struct_ResultVoidStr {
 	err := webdriver.ClearElement(sessionId.String(), elementId.String())
@@ -795,6 +1877,50 @@ func roc_fx_element_is_displayed(sessionId, elementId *RocStr) C.struct_ResultVo
 	}
 }
 
+//export roc_fx_element_is_enabled
+func roc_fx_element_is_enabled(sessionId, elementId *RocStr) C.struct_ResultVoidStr {
+	isEnabled, err := webdriver.IsElementEnabled(sessionId.String(), elementId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	if isEnabled {
+		return createRocResultStr(RocOk, "true")
+	} else {
+		return createRocResultStr(RocOk, "false")
+	}
+}
+
+//export roc_fx_element_get_computed_role
+func roc_fx_element_get_computed_role(sessionId, elementId *RocStr) C.struct_ResultVoidStr {
+	role, err := webdriver.GetElementComputedRole(sessionId.String(), elementId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, role)
+}
+
+//export roc_fx_element_get_computed_label
+func roc_fx_element_get_computed_label(sessionId, elementId *RocStr) C.struct_ResultVoidStr {
+	label, err := webdriver.GetElementComputedLabel(sessionId.String(), elementId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, label)
+}
+
+//export roc_fx_browser_get_active_element
+func roc_fx_browser_get_active_element(sessionId *RocStr) C.struct_ResultVoidStr {
+	elementId, err := webdriver.GetActiveElement(sessionId.String())
+	if err != nil {
+		return createRocResultStr(RocErr, err.Error())
+	}
+
+	return createRocResultStr(RocOk, elementId)
+}
+
 //export roc_fx_get_page_source
 func roc_fx_get_page_source(sessionId *RocStr) C.struct_ResultVoidStr {
 	sourceHtml, err := webdriver.GetPageSource(sessionId.String())
@@ -832,6 +1958,46 @@ func roc_fx_is_verbose() int64 {
 	return int64(isVerboseInt)
 }
 
+//export roc_fx_is_setup_run
+func roc_fx_is_setup_run() int64 {
+	isSetupRunInt := 0
+	if options.SetupOnly || options.PrintBrowserVersionOnly {
+		isSetupRunInt = 1
+	}
+
+	return int64(isSetupRunInt)
+}
+
+// runs the --setup or --print-browser-version-only step instead of the tests - returns the exit code
+
+//export roc_fx_run_setup
+func roc_fx_run_setup() int64 {
+	browser, err := getBrowser()
+	if err != nil {
+		fmt.Println(utils.FG_RED+"Setup failed with: "+utils.RESET, err)
+		return 1
+	}
+
+	if options.PrintBrowserVersionOnly {
+		fmt.Printf("%s", browser.Version())
+		return 0
+	}
+
+	if options.DriverUrl != "" {
+		fmt.Println("Using a remote driver - nothing to set up.")
+		return 0
+	}
+
+	err = browser.Download()
+	if err != nil {
+		fmt.Println(utils.FG_RED+"Setup failed with: "+utils.RESET, err)
+		return 1
+	}
+
+	fmt.Println("Browser and driver ready.")
+	return 0
+}
+
 //export roc_fx_create_dir_if_not_exist
 func roc_fx_create_dir_if_not_exist(path *RocStr) C.struct_ResultVoidStr {
 	err := os.MkdirAll(filepath.Dir(path.String()), os.ModePerm)
@@ -953,6 +2119,24 @@ func createRocResult_ListI64_Str(resultType RocResultType, intList []int64, erro
 	return result
 }
 
+func createRocResult_ListU8_Str(resultType RocResultType, byteList []byte, error string) C.struct_ResultListStr {
+	var result C.struct_ResultListStr
+
+	result.disciminant = C.uchar(resultType)
+
+	if resultType == RocOk {
+		rocList := NewRocList(byteList)
+		payloadPtr := unsafe.Pointer(&result.payload)
+		*(*C.struct_RocList)(payloadPtr) = rocList.C()
+	} else {
+		rocStr := NewRocStr(error)
+		payloadPtr := unsafe.Pointer(&result.payload)
+		*(*C.struct_RocStr)(payloadPtr) = rocStr.C()
+	}
+
+	return result
+}
+
 func createRocResult_ListF64_Str(resultType RocResultType, floatList []float64, error string) C.struct_ResultListStr {
 	var result C.struct_ResultListStr
 
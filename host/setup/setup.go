@@ -11,14 +11,19 @@ type BrowserPaths struct {
 	DirPath        string
 	BrowserPath    string
 	BrowserDirPath string
+	DriverVersion  string
+	DriverOsName   string
 	DriverPath     string
 	DriverDirPath  string
 }
 
 var (
 	// TODO - this will be passed from the roc program
-	BrowserVersion = fmt.Sprintf("Chrome-%s", ChromeVersion)
-	ChromeVersion  = "117.0.5846.0"
+	BrowserVersion        = fmt.Sprintf("Chrome-%s", ChromeVersion)
+	ChromeVersion         = "117.0.5846.0"
+	FirefoxBrowserVersion = fmt.Sprintf("Firefox-%s-geckodriver-%s", FirefoxVersion, GeckodriverVersion)
+	FirefoxVersion        = "128.5.0esr"
+	GeckodriverVersion    = "0.35.0"
 )
 
 func GetChromePaths() (*BrowserPaths, error) {
@@ -56,6 +61,61 @@ func GetChromePaths() (*BrowserPaths, error) {
 		DirPath:        path,
 		BrowserPath:    chromePath,
 		BrowserDirPath: chromeDirPath,
+		DriverVersion:  chromeVersion,
+		DriverOsName:   osName,
+		DriverPath:     driverPath,
+		DriverDirPath:  driverDirPath,
+	}, nil
+}
+
+// GetFirefoxPaths returns the paths of Firefox and geckodriver.
+//
+// Firefox is downloaded only on Linux x64 - the macOS and Windows builds come as installers,
+// so there BrowserPath is empty and geckodriver uses the Firefox installed on the machine.
+func GetFirefoxPaths() (*BrowserPaths, error) {
+	os := fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
+
+	browserFilesDir := "browser_files"
+	firefoxVersion := FirefoxVersion
+	var osName string
+	var driverOsName string
+
+	switch os {
+	case "linux-amd64":
+		osName = "linux-x86_64"
+		driverOsName = "linux64"
+	case "linux-arm64":
+		driverOsName = "linux-aarch64"
+	case "darwin-arm64":
+		driverOsName = "macos-aarch64"
+	case "darwin-amd64":
+		driverOsName = "macos"
+	case "windows-386":
+		driverOsName = "win32"
+	case "windows-amd64":
+		driverOsName = "win64"
+	default:
+		return nil, fmt.Errorf("Unsupported architecture")
+	}
+
+	path := fmt.Sprintf("%s/%s/%s", browserFilesDir, "firefox", firefoxVersion)
+	firefoxPath := ""
+	firefoxDirPath := ""
+	if osName != "" {
+		firefoxPath = fmt.Sprintf("%s/firefox/firefox", path)
+		firefoxDirPath = fmt.Sprintf("%s/firefox", path)
+	}
+	driverDirPath := fmt.Sprintf("%s/geckodriver-%s", path, driverOsName)
+	driverPath := fmt.Sprintf("%s/%s", driverDirPath, getGeckodriverExecutableName())
+
+	return &BrowserPaths{
+		BrowserVersion: firefoxVersion,
+		OsName:         osName,
+		DirPath:        path,
+		BrowserPath:    firefoxPath,
+		BrowserDirPath: firefoxDirPath,
+		DriverVersion:  GeckodriverVersion,
+		DriverOsName:   driverOsName,
 		DriverPath:     driverPath,
 		DriverDirPath:  driverDirPath,
 	}, nil
@@ -71,3 +131,11 @@ func getChromeExecutablePath() string {
 
 	return "chrome"
 }
+
+func getGeckodriverExecutableName() string {
+	if runtime.GOOS == "windows" {
+		return "geckodriver.exe"
+	}
+
+	return "geckodriver"
+}
@@ -0,0 +1,241 @@
+package webdriver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const cdpCommandTimeout = 30 * time.Second
+
+type cdpMessage struct {
+	Id     int64           `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// CdpConnection is a Chrome DevTools Protocol connection to a single page target.
+//
+// Events are delivered to the listeners one by one, in the order the browser sent them,
+// so a listener can send commands without blocking the connection.
+type CdpConnection struct {
+	ws        *websocketConn
+	lock      sync.Mutex
+	nextId    int64
+	pending   map[int64]chan cdpMessage
+	listeners map[string][]func(params json.RawMessage)
+	events    []cdpMessage
+	newEvent  chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+	// why the connection failed - returned by the following commands
+	failure error
+}
+
+type cdpTarget struct {
+	Id                   string `json:"id"`
+	Type                 string `json:"type"`
+	WebSocketDebuggerUrl string `json:"webSocketDebuggerUrl"`
+}
+
+// OpenCdpConnection connects to the page target with the given id, e.g. a chromedriver window handle.
+func OpenCdpConnection(debuggerAddress, targetId string) (*CdpConnection, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/json/list", debuggerAddress))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var targets []cdpTarget
+	err = json.NewDecoder(resp.Body).Decode(&targets)
+	if err != nil {
+		return nil, err
+	}
+
+	// older chromedrivers prefix the target id in the window handles
+	targetId = strings.TrimPrefix(targetId, "CDwindow-")
+
+	wsUrl := ""
+	for _, target := range targets {
+		if target.Type == "page" && strings.EqualFold(target.Id, targetId) {
+			wsUrl = target.WebSocketDebuggerUrl
+			break
+		}
+	}
+
+	if wsUrl == "" {
+		return nil, fmt.Errorf("could not find the DevTools target for the window %s", targetId)
+	}
+
+	ws, err := dialWebsocket(wsUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &CdpConnection{
+		ws:        ws,
+		pending:   map[int64]chan cdpMessage{},
+		listeners: map[string][]func(params json.RawMessage){},
+		newEvent:  make(chan struct{}, 1),
+		closed:    make(chan struct{}),
+	}
+
+	go connection.readLoop()
+	go connection.dispatchLoop()
+
+	return connection, nil
+}
+
+// Send runs a command and waits for its result.
+func (c *CdpConnection) Send(method string, params interface{}) (json.RawMessage, error) {
+	select {
+	case <-c.closed:
+		return nil, c.closedError()
+	default:
+	}
+
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+
+	paramsJson, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.nextId++
+	id := c.nextId
+	responseChan := make(chan cdpMessage, 1)
+	c.pending[id] = responseChan
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		delete(c.pending, id)
+		c.lock.Unlock()
+	}()
+
+	message, err := json.Marshal(cdpMessage{Id: id, Method: method, Params: paramsJson})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.ws.writeMessage(message)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case response := <-responseChan:
+		if response.Error != nil {
+			return nil, fmt.Errorf("%s failed: %s (%d)", method, response.Error.Message, response.Error.Code)
+		}
+
+		return response.Result, nil
+	case <-c.closed:
+		return nil, c.closedError()
+	case <-time.After(cdpCommandTimeout):
+		return nil, fmt.Errorf("%s timed out after %s", method, cdpCommandTimeout)
+	}
+}
+
+// On registers a listener for an event, e.g. "Fetch.requestPaused".
+func (c *CdpConnection) On(event string, listener func(params json.RawMessage)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.listeners[event] = append(c.listeners[event], listener)
+}
+
+func (c *CdpConnection) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = c.ws.close()
+	})
+
+	return err
+}
+
+func (c *CdpConnection) closedError() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.failure != nil {
+		return fmt.Errorf("the DevTools connection failed: %w", c.failure)
+	}
+
+	return errors.New("the DevTools connection is closed")
+}
+
+func (c *CdpConnection) readLoop() {
+	for {
+		data, err := c.ws.readMessage()
+		if err != nil {
+			if !isClosedConnectionError(err) {
+				c.lock.Lock()
+				c.failure = err
+				c.lock.Unlock()
+			}
+			c.Close()
+			return
+		}
+
+		var message cdpMessage
+		err = json.Unmarshal(data, &message)
+		if err != nil {
+			continue
+		}
+
+		c.lock.Lock()
+		if message.Id != 0 {
+			responseChan, ok := c.pending[message.Id]
+			if ok {
+				responseChan <- message
+			}
+		} else if message.Method != "" {
+			c.events = append(c.events, message)
+			select {
+			case c.newEvent <- struct{}{}:
+			default:
+			}
+		}
+		c.lock.Unlock()
+	}
+}
+
+func (c *CdpConnection) dispatchLoop() {
+	for {
+		select {
+		case <-c.newEvent:
+		case <-c.closed:
+			return
+		}
+
+		for {
+			c.lock.Lock()
+			if len(c.events) == 0 {
+				c.lock.Unlock()
+				break
+			}
+			event := c.events[0]
+			c.events = c.events[1:]
+			listeners := append([]func(params json.RawMessage){}, c.listeners[event.Method]...)
+			c.lock.Unlock()
+
+			for _, listener := range listeners {
+				listener(event.Params)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package webdriver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// the temporary files of the downloads in progress - Chrome (.crdownload) and Firefox (.part)
+var inProgressDownloadSuffixes = []string{".crdownload", ".part"}
+
+// ListDownloads returns the names of the finished downloads in the directory, sorted by name.
+func ListDownloads(downloadDir string) ([]string, error) {
+	files, _, err := readDownloadDir(downloadDir)
+	return files, err
+}
+
+// WaitForDownload waits until a file matching the name glob pattern is downloaded,
+// and no other download is in progress - returns the name of the first matching file.
+func WaitForDownload(downloadDir, namePattern string, timeout time.Duration) (string, error) {
+	matcher := globToRegexp(namePattern)
+	start := time.Now()
+
+	for {
+		files, inProgress, err := readDownloadDir(downloadDir)
+		if err != nil {
+			return "", err
+		}
+
+		if inProgress == 0 {
+			for _, file := range files {
+				if matcher.MatchString(file) {
+					return file, nil
+				}
+			}
+		}
+
+		if time.Since(start) >= timeout {
+			if inProgress > 0 {
+				return "", fmt.Errorf("Timeout::the download of \"%s\" did not finish, %d download(s) still in progress (waited for %dms)", namePattern, inProgress, timeout.Milliseconds())
+			}
+
+			return "", fmt.Errorf("Timeout::no file matching \"%s\" was downloaded (waited for %dms)", namePattern, timeout.Milliseconds())
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// ReadDownload returns the content of a finished download.
+func ReadDownload(downloadDir, fileName string) ([]byte, error) {
+	// only the files directly in the download directory can be read
+	if fileName != filepath.Base(fileName) {
+		return nil, fmt.Errorf("invalid download file name: %s", fileName)
+	}
+
+	return os.ReadFile(filepath.Join(downloadDir, fileName))
+}
+
+func readDownloadDir(downloadDir string) ([]string, int, error) {
+	entries, err := os.ReadDir(downloadDir)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	files := []string{}
+	inProgress := 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		if isInProgressDownload(entry.Name()) {
+			inProgress++
+		} else {
+			files = append(files, entry.Name())
+		}
+	}
+
+	sort.Strings(files)
+
+	return files, inProgress, nil
+}
+
+func isInProgressDownload(fileName string) bool {
+	for _, suffix := range inProgressDownloadSuffixes {
+		if strings.HasSuffix(fileName, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,242 @@
+package webdriver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+type HeaderEntry struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Route tells what to do with the requests matching its URL pattern.
+//
+// The pattern is a glob - "*" matches any characters and "?" matches a single character.
+type Route struct {
+	UrlPattern string `json:"urlPattern"`
+	// "block", "fulfill" or "continue"
+	Action string `json:"action"`
+	// fulfill - the stubbed response, the body is read from BodyFile when it is not empty
+	Status   int           `json:"status"`
+	Headers  []HeaderEntry `json:"headers"`
+	Body     string        `json:"body"`
+	BodyFile string        `json:"bodyFile"`
+	// continue - the request overrides, empty values keep the original request
+	Url      string `json:"url"`
+	Method   string `json:"method"`
+	PostData string `json:"postData"`
+}
+
+type route struct {
+	Route
+	matcher *regexp.Regexp
+	body    []byte
+}
+
+// NetworkInterceptor pauses the requests matching its routes (with the CDP Fetch domain)
+// and blocks, stubs or modifies them. The most recently added matching route wins.
+type NetworkInterceptor struct {
+	connection *CdpConnection
+	lock       sync.Mutex
+	routes     []route
+	// the first intercepted request that could not be handled - returned by the next AddRoute or ClearRoutes
+	handleErr error
+}
+
+type requestPaused_Params struct {
+	RequestId string `json:"requestId"`
+	Request   struct {
+		Url     string            `json:"url"`
+		Method  string            `json:"method"`
+		Headers map[string]string `json:"headers"`
+	} `json:"request"`
+}
+
+func NewNetworkInterceptor(connection *CdpConnection) *NetworkInterceptor {
+	interceptor := &NetworkInterceptor{connection: connection}
+	connection.On("Fetch.requestPaused", interceptor.handleRequestPaused)
+
+	return interceptor
+}
+
+func (n *NetworkInterceptor) AddRoute(newRoute Route) error {
+	err := n.takeHandleError()
+	if err != nil {
+		return err
+	}
+
+	switch newRoute.Action {
+	case "block", "fulfill", "continue":
+	default:
+		return fmt.Errorf("unknown route action: %s", newRoute.Action)
+	}
+
+	body := []byte(newRoute.Body)
+	if newRoute.BodyFile != "" {
+		fileBody, err := os.ReadFile(newRoute.BodyFile)
+		if err != nil {
+			return err
+		}
+		body = fileBody
+	}
+
+	n.lock.Lock()
+	n.routes = append(n.routes, route{Route: newRoute, matcher: globToRegexp(newRoute.UrlPattern), body: body})
+	patterns := n.fetchPatterns()
+	n.lock.Unlock()
+
+	_, err = n.connection.Send("Fetch.enable", map[string]interface{}{
+		"patterns": patterns,
+	})
+	return err
+}
+
+func (n *NetworkInterceptor) ClearRoutes() error {
+	n.lock.Lock()
+	n.routes = nil
+	n.lock.Unlock()
+
+	_, err := n.connection.Send("Fetch.disable", nil)
+	if err != nil {
+		return err
+	}
+
+	return n.takeHandleError()
+}
+
+func (n *NetworkInterceptor) takeHandleError() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	err := n.handleErr
+	n.handleErr = nil
+
+	return err
+}
+
+func (n *NetworkInterceptor) fetchPatterns() []map[string]interface{} {
+	patterns := []map[string]interface{}{}
+	for _, r := range n.routes {
+		patterns = append(patterns, map[string]interface{}{
+			"urlPattern":   r.UrlPattern,
+			"requestStage": "Request",
+		})
+	}
+
+	return patterns
+}
+
+func (n *NetworkInterceptor) findRoute(url string) (route, bool) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for i := len(n.routes) - 1; i >= 0; i-- {
+		if n.routes[i].matcher.MatchString(url) {
+			return n.routes[i], true
+		}
+	}
+
+	return route{}, false
+}
+
+func (n *NetworkInterceptor) handleRequestPaused(paramsJson json.RawMessage) {
+	var params requestPaused_Params
+	err := json.Unmarshal(paramsJson, &params)
+	if err != nil {
+		return
+	}
+
+	matchedRoute, ok := n.findRoute(params.Request.Url)
+	if !ok {
+		_, err = n.connection.Send("Fetch.continueRequest", map[string]interface{}{
+			"requestId": params.RequestId,
+		})
+	} else {
+		switch matchedRoute.Action {
+		case "block":
+			_, err = n.connection.Send("Fetch.failRequest", map[string]interface{}{
+				"requestId":   params.RequestId,
+				"errorReason": "BlockedByClient",
+			})
+		case "fulfill":
+			_, err = n.connection.Send("Fetch.fulfillRequest", map[string]interface{}{
+				"requestId":       params.RequestId,
+				"responseCode":    matchedRoute.Status,
+				"responseHeaders": append([]HeaderEntry{}, matchedRoute.Headers...),
+				"body":            base64.StdEncoding.EncodeToString(matchedRoute.body),
+			})
+		case "continue":
+			_, err = n.connection.Send("Fetch.continueRequest", continueRequestParams(params, matchedRoute))
+		}
+	}
+
+	if err != nil {
+		n.lock.Lock()
+		if n.handleErr == nil {
+			n.handleErr = fmt.Errorf("could not handle the intercepted request %s: %w", params.Request.Url, err)
+		}
+		n.lock.Unlock()
+	}
+}
+
+func continueRequestParams(params requestPaused_Params, matchedRoute route) map[string]interface{} {
+	continueParams := map[string]interface{}{
+		"requestId": params.RequestId,
+	}
+
+	if matchedRoute.Url != "" {
+		continueParams["url"] = matchedRoute.Url
+	}
+
+	if matchedRoute.Method != "" {
+		continueParams["method"] = matchedRoute.Method
+	}
+
+	if matchedRoute.PostData != "" {
+		continueParams["postData"] = base64.StdEncoding.EncodeToString([]byte(matchedRoute.PostData))
+	}
+
+	if len(matchedRoute.Headers) > 0 {
+		// CDP replaces all request headers - the overrides are merged into the original ones
+		headers := map[string]string{}
+		for name, value := range params.Request.Headers {
+			headers[strings.ToLower(name)] = value
+		}
+		for _, header := range matchedRoute.Headers {
+			headers[strings.ToLower(header.Name)] = header.Value
+		}
+
+		headerEntries := []HeaderEntry{}
+		for name, value := range headers {
+			headerEntries = append(headerEntries, HeaderEntry{Name: name, Value: value})
+		}
+		continueParams["headers"] = headerEntries
+	}
+
+	return continueParams
+}
+
+// globToRegexp converts a CDP url pattern ("*" - any characters, "?" - a single character) to a regexp
+func globToRegexp(pattern string) *regexp.Regexp {
+	var builder strings.Builder
+	builder.WriteString("^")
+	for _, char := range pattern {
+		switch char {
+		case '*':
+			builder.WriteString(".*")
+		case '?':
+			builder.WriteString(".")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	builder.WriteString("$")
+
+	return regexp.MustCompile(builder.String())
+}
@@ -0,0 +1,313 @@
+package webdriver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordedRequest is a request made by the page, together with its response when it already arrived.
+type RecordedRequest struct {
+	RequestId    string        `json:"requestId"`
+	Url          string        `json:"url"`
+	Method       string        `json:"method"`
+	Headers      []HeaderEntry `json:"headers"`
+	PostData     string        `json:"postData"`
+	ResourceType string        `json:"resourceType"`
+	// "pending", "finished" or "failed"
+	State           string        `json:"state"`
+	Status          int           `json:"status"`
+	StatusText      string        `json:"statusText"`
+	ResponseHeaders []HeaderEntry `json:"responseHeaders"`
+	ErrorText       string        `json:"errorText"`
+	taken           bool
+}
+
+type RecordedResponse struct {
+	Url        string        `json:"url"`
+	Status     int           `json:"status"`
+	StatusText string        `json:"statusText"`
+	Headers    []HeaderEntry `json:"headers"`
+	Body       string        `json:"body"`
+}
+
+// NetworkRecorder records the requests of a page with the CDP Network domain.
+type NetworkRecorder struct {
+	connection *CdpConnection
+	lock       sync.Mutex
+	requests   []*RecordedRequest
+	// the latest request with the id - redirects reuse the id of the original request
+	byId map[string]*RecordedRequest
+	// time of the last request event - used to detect the network idle
+	lastActivity time.Time
+}
+
+type cdpResponse struct {
+	Url        string            `json:"url"`
+	Status     int               `json:"status"`
+	StatusText string            `json:"statusText"`
+	Headers    map[string]string `json:"headers"`
+}
+
+type requestWillBeSent_Params struct {
+	RequestId string `json:"requestId"`
+	Request   struct {
+		Url      string            `json:"url"`
+		Method   string            `json:"method"`
+		Headers  map[string]string `json:"headers"`
+		PostData string            `json:"postData"`
+	} `json:"request"`
+	Type             string       `json:"type"`
+	RedirectResponse *cdpResponse `json:"redirectResponse"`
+}
+
+type responseReceived_Params struct {
+	RequestId string      `json:"requestId"`
+	Response  cdpResponse `json:"response"`
+}
+
+type loadingFinished_Params struct {
+	RequestId string `json:"requestId"`
+}
+
+type loadingFailed_Params struct {
+	RequestId string `json:"requestId"`
+	ErrorText string `json:"errorText"`
+}
+
+type getResponseBody_Result struct {
+	Body          string `json:"body"`
+	Base64Encoded bool   `json:"base64Encoded"`
+}
+
+func NewNetworkRecorder(connection *CdpConnection) (*NetworkRecorder, error) {
+	recorder := &NetworkRecorder{
+		connection:   connection,
+		byId:         map[string]*RecordedRequest{},
+		lastActivity: time.Now(),
+	}
+
+	connection.On("Network.requestWillBeSent", recorder.handleRequestWillBeSent)
+	connection.On("Network.responseReceived", recorder.handleResponseReceived)
+	connection.On("Network.loadingFinished", recorder.handleLoadingFinished)
+	connection.On("Network.loadingFailed", recorder.handleLoadingFailed)
+
+	_, err := connection.Send("Network.enable", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return recorder, nil
+}
+
+// Requests returns all requests recorded so far.
+func (r *NetworkRecorder) Requests() []RecordedRequest {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	requests := make([]RecordedRequest, 0, len(r.requests))
+	for _, request := range r.requests {
+		requests = append(requests, *request)
+	}
+
+	return requests
+}
+
+// TakeResponse returns the first finished response matching the URL glob pattern
+// that was not taken before - nil when there is none yet.
+func (r *NetworkRecorder) TakeResponse(urlPattern string) (*RecordedResponse, error) {
+	matcher := globToRegexp(urlPattern)
+
+	r.lock.Lock()
+	var found *RecordedRequest
+	for _, request := range r.requests {
+		if !request.taken && request.State == "finished" && matcher.MatchString(request.Url) {
+			request.taken = true
+			found = request
+			break
+		}
+	}
+	r.lock.Unlock()
+
+	if found == nil {
+		return nil, nil
+	}
+
+	response := &RecordedResponse{
+		Url:        found.Url,
+		Status:     found.Status,
+		StatusText: found.StatusText,
+		Headers:    found.ResponseHeaders,
+	}
+
+	if found.Status >= 300 && found.Status < 400 {
+		// redirects have no body
+		return response, nil
+	}
+
+	result, err := r.connection.Send("Network.getResponseBody", map[string]interface{}{
+		"requestId": found.RequestId,
+	})
+	if err != nil {
+		// e.g. the body was evicted from the browser buffer, or the page navigated away
+		return response, nil
+	}
+
+	var body getResponseBody_Result
+	err = json.Unmarshal(result, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body.Base64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(body.Body)
+		if err != nil {
+			return nil, err
+		}
+		body.Body = string(decoded)
+	}
+
+	// binary bodies are not valid utf8 - Roc strings have to be
+	response.Body = strings.ToValidUTF8(body.Body, "�")
+
+	return response, nil
+}
+
+// WaitForIdle waits until there are no requests in flight for the idle time.
+func (r *NetworkRecorder) WaitForIdle(idleTime, timeout time.Duration) error {
+	start := time.Now()
+
+	for {
+		r.lock.Lock()
+		inFlight := 0
+		for _, request := range r.requests {
+			if request.State == "pending" {
+				inFlight++
+			}
+		}
+		idleFor := time.Since(r.lastActivity)
+		r.lock.Unlock()
+
+		if inFlight == 0 && idleFor >= idleTime {
+			return nil
+		}
+
+		if time.Since(start) >= timeout {
+			return fmt.Errorf("Timeout::the network was not idle for %dms, %d request(s) still in flight (waited for %dms)", idleTime.Milliseconds(), inFlight, timeout.Milliseconds())
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func (r *NetworkRecorder) handleRequestWillBeSent(paramsJson json.RawMessage) {
+	var params requestWillBeSent_Params
+	err := json.Unmarshal(paramsJson, &params)
+	if err != nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.lastActivity = time.Now()
+
+	previous, ok := r.byId[params.RequestId]
+	if ok && params.RedirectResponse != nil {
+		previous.setResponse(*params.RedirectResponse)
+		previous.State = "finished"
+	}
+
+	request := &RecordedRequest{
+		RequestId:       params.RequestId,
+		Url:             params.Request.Url,
+		Method:          params.Request.Method,
+		Headers:         toHeaderEntries(params.Request.Headers),
+		PostData:        params.Request.PostData,
+		ResourceType:    params.Type,
+		State:           "pending",
+		ResponseHeaders: []HeaderEntry{},
+	}
+
+	r.requests = append(r.requests, request)
+	r.byId[params.RequestId] = request
+}
+
+func (r *NetworkRecorder) handleResponseReceived(paramsJson json.RawMessage) {
+	var params responseReceived_Params
+	err := json.Unmarshal(paramsJson, &params)
+	if err != nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.lastActivity = time.Now()
+
+	request, ok := r.byId[params.RequestId]
+	if ok {
+		request.setResponse(params.Response)
+	}
+}
+
+func (r *NetworkRecorder) handleLoadingFinished(paramsJson json.RawMessage) {
+	var params loadingFinished_Params
+	err := json.Unmarshal(paramsJson, &params)
+	if err != nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.lastActivity = time.Now()
+
+	request, ok := r.byId[params.RequestId]
+	if ok {
+		request.State = "finished"
+	}
+}
+
+func (r *NetworkRecorder) handleLoadingFailed(paramsJson json.RawMessage) {
+	var params loadingFailed_Params
+	err := json.Unmarshal(paramsJson, &params)
+	if err != nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.lastActivity = time.Now()
+
+	request, ok := r.byId[params.RequestId]
+	if ok {
+		request.State = "failed"
+		request.ErrorText = params.ErrorText
+	}
+}
+
+func (request *RecordedRequest) setResponse(response cdpResponse) {
+	request.Status = response.Status
+	request.StatusText = response.StatusText
+	request.ResponseHeaders = toHeaderEntries(response.Headers)
+}
+
+// toHeaderEntries converts the CDP headers object to a list sorted by the header name
+func toHeaderEntries(headers map[string]string) []HeaderEntry {
+	entries := make([]HeaderEntry, 0, len(headers))
+	for name, value := range headers {
+		entries = append(entries, HeaderEntry{Name: name, Value: value})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
+}
@@ -4,49 +4,65 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"host/setup"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
-const baseUrl = "http://localhost:9515"
+// the endpoint of the driver used in this run - set with SetBaseUrl before the first request
+var baseUrl string
+
+// SetBaseUrl points all requests to a WebDriver endpoint,
+// e.g. a local chromedriver - "http://localhost:41235",
+// or a remote Selenium Grid - "http://my-grid:4444/wd/hub".
+func SetBaseUrl(url string) {
+	baseUrl = strings.TrimSuffix(url, "/")
+}
 
 type CreateSession_ResponseValue struct {
-	SessionID string `json:"sessionId"`
+	SessionID    string                 `json:"sessionId"`
+	Capabilities map[string]interface{} `json:"capabilities"`
 }
 
 type CreateSession_Response struct {
 	Value CreateSession_ResponseValue `json:"value"`
 }
 
-type SessionOptions struct {
-	Headless        bool
-	WindowSize      string
-	ImplicitTimeout uint64
-	PageLoadTimeout uint64
-	ScriptTimeout   uint64
+// MobileEmulation is the chromedriver "mobileEmulation" option - sizes in CSS pixels.
+type MobileEmulation struct {
+	DeviceMetrics DeviceMetrics `json:"deviceMetrics"`
+	UserAgent     string        `json:"userAgent,omitempty"`
 }
 
-func CreateSession(options SessionOptions) (string, error) {
-	url := fmt.Sprintf("%s/session", baseUrl)
-	paths, err := setup.GetChromePaths()
-	if err != nil {
-		return "", err
-	}
+type DeviceMetrics struct {
+	Width      uint64  `json:"width"`
+	Height     uint64  `json:"height"`
+	PixelRatio float64 `json:"pixelRatio"`
+	Touch      bool    `json:"touch"`
+	Mobile     bool    `json:"mobile"`
+}
 
-	binaryArgs := []string{
-		"--window-size=" + options.WindowSize,
-	}
+type SessionOptions struct {
+	// W3C browser name, e.g. "chrome" or "firefox"
+	BrowserName string
+	// vendor specific capabilities, e.g. {"goog:chromeOptions": {...}}
+	BrowserCapabilities map[string]interface{}
+	ImplicitTimeout     uint64
+	PageLoadTimeout     uint64
+	ScriptTimeout       uint64
+}
 
-	if options.Headless {
-		binaryArgs = append(binaryArgs, "--headless")
-	}
+// CreateSession returns the id and the capabilities of the new session.
+func CreateSession(options SessionOptions) (string, map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/session", baseUrl)
 
 	reqBody := map[string]interface{}{
 		"capabilities": map[string]interface{}{
 			"alwaysMatch": map[string]interface{}{
+				"browserName": options.BrowserName,
 				"timeouts": map[string]interface{}{
 					"implicit": options.ImplicitTimeout,
 					"pageLoad": options.PageLoadTimeout,
@@ -54,28 +70,23 @@ func CreateSession(options SessionOptions) (string, error) {
 				},
 			},
 			"firstMatch": []map[string]interface{}{
-				{
-					"goog:chromeOptions": map[string]interface{}{
-						"binary": paths.BrowserPath,
-						"args":   binaryArgs,
-					},
-				},
+				options.BrowserCapabilities,
 			},
 		},
 	}
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	var response CreateSession_Response
 	err = makeHttpRequest("POST", url, bytes.NewBuffer(jsonData), &response)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	return response.Value.SessionID, nil
+	return response.Value.SessionID, response.Value.Capabilities, nil
 }
 
 func DeleteSession(sessionId string) error {
@@ -89,6 +100,81 @@ func DeleteSession(sessionId string) error {
 	return nil
 }
 
+// Timeouts of a session in milliseconds - a nil Script timeout means scripts never time out.
+type Timeouts struct {
+	Implicit *uint64 `json:"implicit"`
+	PageLoad *uint64 `json:"pageLoad"`
+	Script   *uint64 `json:"script"`
+}
+
+type GetTimeouts_Response struct {
+	Value Timeouts `json:"value"`
+}
+
+func GetTimeouts(sessionId string) (Timeouts, error) {
+	url := fmt.Sprintf("%s/session/%s/timeouts", baseUrl, sessionId)
+
+	var response GetTimeouts_Response
+	err := makeHttpRequest("GET", url, nil, &response)
+	if err != nil {
+		return Timeouts{}, err
+	}
+
+	return response.Value, nil
+}
+
+func SetTimeouts(sessionId string, timeouts Timeouts) error {
+	url := fmt.Sprintf("%s/session/%s/timeouts", baseUrl, sessionId)
+
+	jsonData, err := json.Marshal(timeouts)
+	if err != nil {
+		return err
+	}
+
+	err = makeHttpRequest[any]("POST", url, bytes.NewBuffer(jsonData), nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type LogEntry struct {
+	// e.g. "SEVERE", "WARNING", "INFO"
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	// e.g. "console-api", "javascript", "network"
+	Source string `json:"source"`
+	// unix time in milliseconds
+	Timestamp float64 `json:"timestamp"`
+}
+
+type GetLogs_Response struct {
+	Value []LogEntry `json:"value"`
+}
+
+// GetLogs returns the log entries collected since the last call - not part of W3C, supported by chromedriver.
+func GetLogs(sessionId, logType string) ([]LogEntry, error) {
+	url := fmt.Sprintf("%s/session/%s/se/log", baseUrl, sessionId)
+
+	reqBody := map[string]interface{}{
+		"type": logType,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var response GetLogs_Response
+	err = makeHttpRequest("POST", url, bytes.NewBuffer(jsonData), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Value, nil
+}
+
 func NavigateTo(sessionId, url string) error {
 	requestUrl := fmt.Sprintf("%s/session/%s/url", baseUrl, sessionId)
 
@@ -164,12 +250,25 @@ func NavigateForward(sessionId string) error {
 }
 
 func SwitchToFrameByElementId(sessionId, elementId string) error {
+	return switchToFrame(sessionId, map[string]interface{}{
+		"element-6066-11e4-a52e-4f735466cecf": elementId,
+	})
+}
+
+func SwitchToFrameByIndex(sessionId string, index int64) error {
+	return switchToFrame(sessionId, index)
+}
+
+func SwitchToTopLevelFrame(sessionId string) error {
+	return switchToFrame(sessionId, nil)
+}
+
+// switchToFrame sends the "id" of the W3C Switch To Frame command - an element reference, a frame index or null for the top-level context.
+func switchToFrame(sessionId string, id interface{}) error {
 	requestUrl := fmt.Sprintf("%s/session/%s/frame", baseUrl, sessionId)
 
 	reqBody := map[string]interface{}{
-		"id": map[string]interface{}{
-			"element-6066-11e4-a52e-4f735466cecf": elementId,
-		},
+		"id": id,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -292,6 +391,19 @@ func BrowserGetScreenshot(sessionId string) (string, error) {
 	return response.Value, nil
 }
 
+func ElementGetScreenshot(sessionId, elementId string) (string, error) {
+	requestUrl := fmt.Sprintf("%s/session/%s/element/%s/screenshot", baseUrl, sessionId, elementId)
+
+	var response GetScreenshot_Response
+
+	err := makeHttpRequest("GET", requestUrl, nil, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.Value, nil
+}
+
 type GetPageSource_Response struct {
 	Value string `json:"value"`
 }
@@ -315,23 +427,24 @@ type ExecuteJs_Response struct {
 func ExecuteJs(sessionId, jsString, argsString string) (string, error) {
 	requestUrl := fmt.Sprintf("%s/session/%s/execute/sync", baseUrl, sessionId)
 
-	jsEscaped, err := json.Marshal(jsString)
-	if err != nil {
-		return "", err
-	}
+	return executeScript(requestUrl, jsString, argsString)
+}
 
-	jsonData := []byte(fmt.Sprintf(`{
-		"script": %s,
-    "args": %s
-	}`, jsEscaped, argsString))
+// ExecuteJsAsync runs a script that reports its result by calling the callback
+// passed as the last argument - the driver waits for it up to the session script timeout.
+func ExecuteJsAsync(sessionId, jsString, argsString string) (string, error) {
+	requestUrl := fmt.Sprintf("%s/session/%s/execute/async", baseUrl, sessionId)
 
-	var response ExecuteJs_Response
-	err = makeHttpRequest("POST", requestUrl, bytes.NewBuffer(jsonData), &response)
+	return executeScript(requestUrl, jsString, argsString)
+}
+
+func executeScript(requestUrl, jsString, argsString string) (string, error) {
+	value, err := executeScriptRaw(requestUrl, jsString, argsString)
 	if err != nil {
 		return "", err
 	}
 
-	switch v := response.Value.(type) {
+	switch v := value.(type) {
 
 	case nil:
 		return "", nil
@@ -350,46 +463,176 @@ func ExecuteJs(sessionId, jsString, argsString string) (string, error) {
 	}
 }
 
-// type PdfOptions struct {
-// 	Page        PdfPageOptions
-// 	Margin      PdfMarginOptions
-// 	Scale       float64
-// 	Orientation string
-// 	ShrinkToFit bool
-// 	Background  bool
-// 	PageRages   []string
-// }
-// type PdfPageOptions struct {
-// 	Width  float64
-// 	Height float64
-// }
-// type PdfMarginOptions struct {
-// 	Top    float64
-// 	Bottom float64
-// 	Left   float64
-// 	Right  float64
-// }
-//
-// type BrowserGetPdf_Response struct {
-// 	Value string `json:"value"`
-// }
-//
-// func BrowserGetPdf(sessionId string, pdfOptions PdfOptions) (string, error) {
-// 	requestUrl := fmt.Sprintf("%s/session/%s/print", baseUrl, sessionId)
-//
-// 	jsonData, err := json.Marshal(pdfOptions)
-// 	if err != nil {
-// 		return "", err
-// 	}
+func executeScriptRaw(requestUrl, jsString, argsString string) (interface{}, error) {
+	jsEscaped, err := json.Marshal(jsString)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData := []byte(fmt.Sprintf(`{
+		"script": %s,
+    "args": %s
+	}`, jsEscaped, argsString))
+
+	var response ExecuteJs_Response
+	err = makeHttpRequest("POST", requestUrl, bytes.NewBuffer(jsonData), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Value, nil
+}
+
+// ExecuteJsJson returns the script result as a JSON document - arrays and objects included.
+func ExecuteJsJson(sessionId, jsString, argsString string) (string, error) {
+	requestUrl := fmt.Sprintf("%s/session/%s/execute/sync", baseUrl, sessionId)
+
+	value, err := executeScriptRaw(requestUrl, jsString, argsString)
+	if err != nil {
+		return "", err
+	}
+
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonData), nil
+}
+
+// the key of a W3C element reference - {"element-6066-11e4-a52e-4f735466cecf": "<element id>"}
+const elementReferenceKey = "element-6066-11e4-a52e-4f735466cecf"
+
+// ExecuteJsElements returns the ids of elements returned by the script -
+// the script can return a single element, a list of elements, or null.
+func ExecuteJsElements(sessionId, jsString, argsString string) ([]string, error) {
+	requestUrl := fmt.Sprintf("%s/session/%s/execute/sync", baseUrl, sessionId)
+
+	value, err := executeScriptRaw(requestUrl, jsString, argsString)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+
+	case nil:
+		return []string{}, nil
+
+	case map[string]interface{}:
+		elementId, ok := elementIdFromReference(v)
+		if !ok {
+			return nil, fmt.Errorf("JsReturnTypeError::expected an element, but got: %v", v)
+		}
+		return []string{elementId}, nil
+
+	case []interface{}:
+		elementIds := make([]string, len(v))
+		for i, item := range v {
+			reference, _ := item.(map[string]interface{})
+			elementId, ok := elementIdFromReference(reference)
+			if !ok {
+				return nil, fmt.Errorf("JsReturnTypeError::expected a list of elements, but got: %v at index %d", item, i)
+			}
+			elementIds[i] = elementId
+		}
+		return elementIds, nil
+
+	default:
+		return nil, fmt.Errorf("JsReturnTypeError::expected an element or a list of elements, but got: %v", v)
+	}
+}
+
+func elementIdFromReference(reference map[string]interface{}) (string, bool) {
+	elementId, ok := reference[elementReferenceKey].(string)
+	return elementId, ok
+}
+
+// domMutationsJs installs a MutationObserver on the first call in a page
+// and returns the milliseconds since the last DOM mutation
+const domMutationsJs = `
+if (!window.__r2eDomObserver) {
+	window.__r2eLastMutation = Date.now();
+	window.__r2eDomObserver = new MutationObserver(() => { window.__r2eLastMutation = Date.now(); });
+	window.__r2eDomObserver.observe(document, { childList: true, subtree: true, attributes: true, characterData: true });
+}
+return Date.now() - window.__r2eLastMutation;
+`
+
+// WaitForDomStable waits until there were no DOM mutations for the quiet time.
 //
-// 	var response BrowserGetPdf_Response
-// 	err = makeHttpRequest("GET", requestUrl, bytes.NewBuffer(jsonData), &response)
-// 	if err != nil {
-// 		return "", err
-// 	}
+// A navigation starts a new page - the quiet time is measured again from the first check in it.
+func WaitForDomStable(sessionId string, quietTime, timeout time.Duration) error {
+	start := time.Now()
+
+	for {
+		result, err := ExecuteJs(sessionId, domMutationsJs, "[]")
+		if err != nil {
+			return err
+		}
+
+		sinceLastMutation, err := strconv.ParseFloat(result, 64)
+		if err != nil {
+			return err
+		}
+
+		if time.Duration(sinceLastMutation)*time.Millisecond >= quietTime {
+			return nil
+		}
+
+		if time.Since(start) >= timeout {
+			return fmt.Errorf("Timeout::the DOM was still changing after %dms", timeout.Milliseconds())
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// PdfOptions - page sizes and margins are in cm.
 //
-// 	return response.Value, nil
-// }
+// https://www.w3.org/TR/webdriver2/#print-page
+type PdfOptions struct {
+	Page        PdfPageOptions   `json:"page"`
+	Margin      PdfMarginOptions `json:"margin"`
+	Scale       float64          `json:"scale"`
+	Orientation string           `json:"orientation"`
+	ShrinkToFit bool             `json:"shrinkToFit"`
+	Background  bool             `json:"background"`
+	PageRanges  []string         `json:"pageRanges"`
+}
+
+type PdfPageOptions struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+type PdfMarginOptions struct {
+	Top    float64 `json:"top"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+	Right  float64 `json:"right"`
+}
+
+type BrowserGetPdf_Response struct {
+	Value string `json:"value"`
+}
+
+// BrowserGetPdf returns the printed page as a base64 encoded PDF.
+func BrowserGetPdf(sessionId string, pdfOptions PdfOptions) (string, error) {
+	requestUrl := fmt.Sprintf("%s/session/%s/print", baseUrl, sessionId)
+
+	jsonData, err := json.Marshal(pdfOptions)
+	if err != nil {
+		return "", err
+	}
+
+	var response BrowserGetPdf_Response
+	err = makeHttpRequest("POST", requestUrl, bytes.NewBuffer(jsonData), &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.Value, nil
+}
 
 type GetStatus_ResponseValue struct {
 	Ready bool `json:"ready"`
@@ -451,6 +694,103 @@ func GetWindowRect(sessionId string) (*WindowRect, error) {
 	return &response.Value, nil
 }
 
+type GetWindowHandle_Response struct {
+	Value string `json:"value"`
+}
+
+func GetWindowHandle(sessionId string) (string, error) {
+	url := fmt.Sprintf("%s/session/%s/window", baseUrl, sessionId)
+
+	var response GetWindowHandle_Response
+	err := makeHttpRequest("GET", url, nil, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.Value, nil
+}
+
+type GetWindowHandles_Response struct {
+	Value []string `json:"value"`
+}
+
+func GetWindowHandles(sessionId string) ([]string, error) {
+	url := fmt.Sprintf("%s/session/%s/window/handles", baseUrl, sessionId)
+
+	var response GetWindowHandles_Response
+	err := makeHttpRequest("GET", url, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Value, nil
+}
+
+type NewWindow_ResponseValue struct {
+	Handle string `json:"handle"`
+	Type   string `json:"type"`
+}
+
+type NewWindow_Response struct {
+	Value NewWindow_ResponseValue `json:"value"`
+}
+
+// NewWindow opens a new "tab" or "window" - the type is only a hint for the browser.
+// The current window does not change.
+func NewWindow(sessionId, typeHint string) (string, error) {
+	url := fmt.Sprintf("%s/session/%s/window/new", baseUrl, sessionId)
+
+	reqBody := map[string]interface{}{
+		"type": typeHint,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	var response NewWindow_Response
+	err = makeHttpRequest("POST", url, bytes.NewBuffer(jsonData), &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.Value.Handle, nil
+}
+
+func SwitchToWindow(sessionId, handle string) error {
+	url := fmt.Sprintf("%s/session/%s/window", baseUrl, sessionId)
+
+	reqBody := map[string]interface{}{
+		"handle": handle,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	err = makeHttpRequest[any]("POST", url, bytes.NewBuffer(jsonData), nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CloseWindow closes the current window and returns the handles of the remaining windows.
+func CloseWindow(sessionId string) ([]string, error) {
+	url := fmt.Sprintf("%s/session/%s/window", baseUrl, sessionId)
+
+	var response GetWindowHandles_Response
+	err := makeHttpRequest("DELETE", url, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Value, nil
+}
+
 type ElementRect struct {
 	X      float64 `json:"x"`
 	Y      float64 `json:"y"`
@@ -531,6 +871,38 @@ func Minimize(sessionId string) (*WindowRect, error) {
 	return &response.Value, nil
 }
 
+// NetworkConditions emulated by chromedriver - throughput in bytes per second, -1 disables the throttling.
+type NetworkConditions struct {
+	Offline            bool   `json:"offline"`
+	Latency            uint64 `json:"latency"`
+	DownloadThroughput int64  `json:"download_throughput"`
+	UploadThroughput   int64  `json:"upload_throughput"`
+}
+
+func (conditions NetworkConditions) IsThrottled() bool {
+	return conditions.Offline || conditions.Latency > 0 || conditions.DownloadThroughput >= 0 || conditions.UploadThroughput >= 0
+}
+
+func SetNetworkConditions(sessionId string, conditions NetworkConditions) error {
+	requestUrl := fmt.Sprintf("%s/session/%s/chromium/network_conditions", baseUrl, sessionId)
+
+	reqBody := map[string]interface{}{
+		"network_conditions": conditions,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	err = makeHttpRequest[any]("POST", requestUrl, bytes.NewBuffer(jsonData), nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 type FindElement_Response struct {
 	Value FindElement_ResponseValue `json:"value"`
 }
@@ -583,6 +955,87 @@ func FindElementInElement(sessionId, elementId, using, value string) (string, er
 	return response.Value.ElementId, nil
 }
 
+func GetActiveElement(sessionId string) (string, error) {
+	url := fmt.Sprintf("%s/session/%s/element/active", baseUrl, sessionId)
+
+	var response FindElement_Response
+	err := makeHttpRequest("GET", url, nil, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.Value.ElementId, nil
+}
+
+type GetShadowRoot_ResponseValue struct {
+	ShadowRootId string `json:"shadow-6066-11e4-a52e-4f735466cecf"`
+}
+
+type GetShadowRoot_Response struct {
+	Value GetShadowRoot_ResponseValue `json:"value"`
+}
+
+func GetShadowRoot(sessionId, elementId string) (string, error) {
+	url := fmt.Sprintf("%s/session/%s/element/%s/shadow", baseUrl, sessionId, elementId)
+
+	var response GetShadowRoot_Response
+	err := makeHttpRequest("GET", url, nil, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.Value.ShadowRootId, nil
+}
+
+func FindElementInShadowRoot(sessionId, shadowRootId, using, value string) (string, error) {
+	url := fmt.Sprintf("%s/session/%s/shadow/%s/element", baseUrl, sessionId, shadowRootId)
+
+	reqBody := map[string]interface{}{
+		"using": using,
+		"value": value,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	var response FindElement_Response
+	err = makeHttpRequest("POST", url, bytes.NewBuffer(jsonData), &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.Value.ElementId, nil
+}
+
+func FindElementsInShadowRoot(sessionId, shadowRootId, using, value string) ([]string, error) {
+	url := fmt.Sprintf("%s/session/%s/shadow/%s/elements", baseUrl, sessionId, shadowRootId)
+
+	reqBody := map[string]interface{}{
+		"using": using,
+		"value": value,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var response FindElements_Response
+	err = makeHttpRequest("POST", url, bytes.NewBuffer(jsonData), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	elementIds := make([]string, len(response.Value))
+	for i, element := range response.Value {
+		elementIds[i] = element.ElementId
+	}
+
+	return elementIds, nil
+}
+
 type GetBrowserTitle_Response struct {
 	Value string `json:"value"`
 }
@@ -695,15 +1148,149 @@ func ClickElement(sessionId, elementId string) error {
 	return nil
 }
 
+// keyMappings maps special key names (used as "{name}") to W3C key codes.
+//
+// https://www.w3.org/TR/webdriver2/#keyboard-actions
 var keyMappings = map[string]string{
-	"{enter}": "\uE007",
+	"cancel":     "\uE001",
+	"help":       "\uE002",
+	"backspace":  "\uE003",
+	"tab":        "\uE004",
+	"clear":      "\uE005",
+	"return":     "\uE006",
+	"enter":      "\uE007",
+	"pause":      "\uE00B",
+	"esc":        "\uE00C",
+	"escape":     "\uE00C",
+	"space":      "\uE00D",
+	"pageup":     "\uE00E",
+	"pagedown":   "\uE00F",
+	"end":        "\uE010",
+	"home":       "\uE011",
+	"left":       "\uE012",
+	"leftarrow":  "\uE012",
+	"up":         "\uE013",
+	"uparrow":    "\uE013",
+	"right":      "\uE014",
+	"rightarrow": "\uE014",
+	"down":       "\uE015",
+	"downarrow":  "\uE015",
+	"insert":     "\uE016",
+	"delete":     "\uE017",
+	"del":        "\uE017",
+	"f1":         "\uE031",
+	"f2":         "\uE032",
+	"f3":         "\uE033",
+	"f4":         "\uE034",
+	"f5":         "\uE035",
+	"f6":         "\uE036",
+	"f7":         "\uE037",
+	"f8":         "\uE038",
+	"f9":         "\uE039",
+	"f10":        "\uE03A",
+	"f11":        "\uE03B",
+	"f12":        "\uE03C",
+}
+
+// modifierMappings are keys that can be combined with other keys - e.g. "{ctrl+a}".
+var modifierMappings = map[string]string{
+	"shift":   "\uE008",
+	"ctrl":    "\uE009",
+	"control": "\uE009",
+	"alt":     "\uE00A",
+	"option":  "\uE00A",
+	"meta":    "\uE03D",
+	"cmd":     "\uE03D",
+	"command": "\uE03D",
+}
+
+// replaceSpecialKeys replaces "{name}" sequences with W3C key codes.
+//
+// "{ctrl+a}" is a chord - the modifiers are pressed, the key is typed, and the modifiers are released.
+// "{{" and "}}" are literal braces. Unknown sequences are left as they are.
+func replaceSpecialKeys(text string) string {
+	var result strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		if strings.HasPrefix(text[i:], "{{") || strings.HasPrefix(text[i:], "}}") {
+			result.WriteByte(text[i])
+			i++
+			continue
+		}
+
+		if text[i] != '{' {
+			result.WriteByte(text[i])
+			continue
+		}
+
+		closing := strings.IndexByte(text[i+1:], '}')
+		if closing == -1 {
+			result.WriteByte(text[i])
+			continue
+		}
+
+		// "{ctrl+}}" ends with a literal brace
+		if strings.HasSuffix(text[i+1:i+1+closing], "+") && strings.HasPrefix(text[i+1+closing:], "}}") {
+			closing++
+		}
+
+		codes, ok := parseKeySequence(text[i+1 : i+1+closing])
+		if !ok {
+			result.WriteByte(text[i])
+			continue
+		}
+
+		result.WriteString(codes)
+		i += closing + 1
+	}
+
+	return result.String()
 }
 
-func replaceSpecialKeys(text string) string {
-	for key, code := range keyMappings {
-		text = strings.ReplaceAll(text, key, code)
+// parseKeySequence turns "enter", "ctrl", or "ctrl+shift+a" into W3C key codes.
+func parseKeySequence(sequence string) (string, bool) {
+	name := strings.ToLower(sequence)
+
+	if code, ok := keyMappings[name]; ok {
+		return code, true
+	}
+
+	// a single modifier stays pressed until the end of the text
+	if code, ok := modifierMappings[name]; ok {
+		return code, true
+	}
+
+	if len(sequence) < 3 {
+		return "", false
+	}
+
+	separator := strings.LastIndex(sequence[:len(sequence)-1], "+")
+	if separator == -1 {
+		return "", false
 	}
-	return text
+
+	key := sequence[separator+1:]
+	keyCode, ok := keyMappings[strings.ToLower(key)]
+	if !ok {
+		if utf8.RuneCountInString(key) != 1 {
+			return "", false
+		}
+		keyCode = key
+	}
+
+	pressed := ""
+	released := ""
+	for _, modifier := range strings.Split(strings.ToLower(sequence[:separator]), "+") {
+		code, ok := modifierMappings[modifier]
+		if !ok {
+			return "", false
+		}
+		pressed += code
+		released = code + released
+	}
+
+	// typing a modifier again releases it
+	return pressed + keyCode + released, true
 }
 
 func ElementSendKeys(sessionId, elementId, text string) error {
@@ -728,6 +1315,76 @@ func ElementSendKeys(sessionId, elementId, text string) error {
 	return nil
 }
 
+// ActionSequence is a single input source for the W3C Actions API
+// - "pointer" (mouse, pen, touch), "key", "wheel" or "none".
+//
+// https://www.w3.org/TR/webdriver2/#actions
+type ActionSequence struct {
+	Type       string             `json:"type"`
+	Id         string             `json:"id"`
+	Parameters *PointerParameters `json:"parameters,omitempty"`
+	Actions    []Action           `json:"actions"`
+}
+
+type PointerParameters struct {
+	PointerType string `json:"pointerType"`
+}
+
+// Action is a single tick of an input source.
+// Optional fields are pointers, because 0 is a valid value for most of them.
+type Action struct {
+	Type     string      `json:"type"`
+	Duration *uint64     `json:"duration,omitempty"`
+	Value    string      `json:"value,omitempty"`
+	Button   *int64      `json:"button,omitempty"`
+	Origin   interface{} `json:"origin,omitempty"`
+	X        *int64      `json:"x,omitempty"`
+	Y        *int64      `json:"y,omitempty"`
+	DeltaX   *int64      `json:"deltaX,omitempty"`
+	DeltaY   *int64      `json:"deltaY,omitempty"`
+}
+
+func PerformActions(sessionId string, actions []ActionSequence) error {
+	url := fmt.Sprintf("%s/session/%s/actions", baseUrl, sessionId)
+
+	for i := range actions {
+		if actions[i].Type != "key" {
+			continue
+		}
+
+		for j := range actions[i].Actions {
+			actions[i].Actions[j].Value = replaceSpecialKeys(actions[i].Actions[j].Value)
+		}
+	}
+
+	reqBody := map[string]interface{}{
+		"actions": actions,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	err = makeHttpRequest[any]("POST", url, bytes.NewBuffer(jsonData), nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ReleaseActions(sessionId string) error {
+	url := fmt.Sprintf("%s/session/%s/actions", baseUrl, sessionId)
+
+	err := makeHttpRequest[any]("DELETE", url, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func ClearElement(sessionId, elementId string) error {
 	url := fmt.Sprintf("%s/session/%s/element/%s/clear", baseUrl, sessionId, elementId)
 
@@ -878,6 +1535,50 @@ func IsElementSelected(sessionId, elementId string) (bool, error) {
 	return response.Value, nil
 }
 
+type IsElementEnabled_Response struct {
+	Value bool `json:"value"`
+}
+
+func IsElementEnabled(sessionId, elementId string) (bool, error) {
+	url := fmt.Sprintf("%s/session/%s/element/%s/enabled", baseUrl, sessionId, elementId)
+
+	var response IsElementEnabled_Response
+	err := makeHttpRequest("GET", url, nil, &response)
+	if err != nil {
+		return false, err
+	}
+
+	return response.Value, nil
+}
+
+type GetElementComputed_Response struct {
+	Value string `json:"value"`
+}
+
+func GetElementComputedRole(sessionId, elementId string) (string, error) {
+	url := fmt.Sprintf("%s/session/%s/element/%s/computedrole", baseUrl, sessionId, elementId)
+
+	var response GetElementComputed_Response
+	err := makeHttpRequest("GET", url, nil, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.Value, nil
+}
+
+func GetElementComputedLabel(sessionId, elementId string) (string, error) {
+	url := fmt.Sprintf("%s/session/%s/element/%s/computedlabel", baseUrl, sessionId, elementId)
+
+	var response GetElementComputed_Response
+	err := makeHttpRequest("GET", url, nil, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.Value, nil
+}
+
 type Cookie struct {
 	Name     string  `json:"name"`
 	Value    string  `json:"value"`
@@ -967,19 +1668,71 @@ func GetAllCookies(sessionId string) (*[]Cookie, error) {
 	return &response.Value, nil
 }
 
-type WebDriverNotFoundError struct {
-	Message string
+// ErrorCode is the W3C error code returned by the driver in the "error" field
+// of a failed command, e.g. "no such element".
+//
+// https://www.w3.org/TR/webdriver2/#errors
+type ErrorCode string
+
+const (
+	ErrorCodeNoSuchElement           ErrorCode = "no such element"
+	ErrorCodeStaleElementReference   ErrorCode = "stale element reference"
+	ErrorCodeElementClickIntercepted ErrorCode = "element click intercepted"
+	ErrorCodeElementNotInteractable  ErrorCode = "element not interactable"
+	ErrorCodeInvalidSelector         ErrorCode = "invalid selector"
+	ErrorCodeTimeout                 ErrorCode = "timeout"
+	ErrorCodeScriptTimeout           ErrorCode = "script timeout"
+	ErrorCodeNoSuchWindow            ErrorCode = "no such window"
+	ErrorCodeNoSuchFrame             ErrorCode = "no such frame"
+	ErrorCodeNoSuchAlert             ErrorCode = "no such alert"
+	ErrorCodeNoSuchCookie            ErrorCode = "no such cookie"
+	ErrorCodeUnexpectedAlertOpen     ErrorCode = "unexpected alert open"
+	ErrorCodeSessionNotCreated       ErrorCode = "session not created"
+	ErrorCodeNoSuchShadowRoot        ErrorCode = "no such shadow root"
+	ErrorCodeDetachedShadowRoot      ErrorCode = "detached shadow root"
+)
+
+// errorTags maps the error codes to the names of the Roc tags they are decoded into.
+// The tag name is used as the prefix of the error message passed to Roc, e.g. "StaleElementReference::<message>".
+var errorTags = map[ErrorCode]string{
+	ErrorCodeNoSuchElement:           "ElementNotFound",
+	ErrorCodeStaleElementReference:   "StaleElementReference",
+	ErrorCodeElementClickIntercepted: "ElementClickIntercepted",
+	ErrorCodeElementNotInteractable:  "ElementNotInteractable",
+	ErrorCodeInvalidSelector:         "InvalidSelector",
+	ErrorCodeTimeout:                 "Timeout",
+	ErrorCodeScriptTimeout:           "Timeout",
+	ErrorCodeNoSuchWindow:            "NoSuchWindow",
+	ErrorCodeNoSuchFrame:             "NoSuchFrame",
+	ErrorCodeNoSuchAlert:             "AlertNotFound",
+	ErrorCodeNoSuchCookie:            "CookieNotFound",
+	ErrorCodeUnexpectedAlertOpen:     "UnexpectedAlertOpen",
+	ErrorCodeSessionNotCreated:       "SessionNotCreated",
+	ErrorCodeNoSuchShadowRoot:        "ShadowRootNotFound",
+	ErrorCodeDetachedShadowRoot:      "DetachedShadowRoot",
+}
+
+// WebDriverError is a failed command decoded from the W3C error response body.
+type WebDriverError struct {
+	StatusCode int
+	Code       ErrorCode
+	Message    string
 }
 
-func (e *WebDriverNotFoundError) Error() string {
-	return fmt.Sprintf("WebDriverNotFoundError::%s", e.Message)
+func (e *WebDriverError) Error() string {
+	if tag, ok := errorTags[e.Code]; ok {
+		return fmt.Sprintf("%s::%s", tag, e.Message)
+	}
+
+	return fmt.Sprintf("WebDriverRequest[%d]: %s: %s", e.StatusCode, e.Code, e.Message)
 }
 
-type WebDriverNotFoundResponseBody struct {
-	Value WebDriverNotFoundResponseValue `json:"value"`
+type WebDriverErrorResponseBody struct {
+	Value WebDriverErrorResponseValue `json:"value"`
 }
 
-type WebDriverNotFoundResponseValue struct {
+type WebDriverErrorResponseValue struct {
+	Error   string `json:"error"`
 	Message string `json:"message"`
 }
 
@@ -1005,19 +1758,21 @@ func makeHttpRequest[T any](method, url string, body *bytes.Buffer, result *T) e
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
-		var responseBody WebDriverNotFoundResponseBody
-		err = json.NewDecoder(resp.Body).Decode(&responseBody)
-		if err != nil {
-			return err
-		}
-
-		return &WebDriverNotFoundError{Message: responseBody.Value.Message}
-	}
-
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(io.Reader(resp.Body))
-		return fmt.Errorf("WebDriverRequest[%d]: %s", resp.StatusCode, body)
+
+		var responseBody WebDriverErrorResponseBody
+		err = json.Unmarshal(body, &responseBody)
+		if err != nil || responseBody.Value.Error == "" {
+			// not a W3C error body - e.g. a proxy in front of the driver
+			return fmt.Errorf("WebDriverRequest[%d]: %s", resp.StatusCode, body)
+		}
+
+		return &WebDriverError{
+			StatusCode: resp.StatusCode,
+			Code:       ErrorCode(responseBody.Value.Error),
+			Message:    responseBody.Value.Message,
+		}
 	}
 
 	if result == nil {
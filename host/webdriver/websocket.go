@@ -0,0 +1,225 @@
+package webdriver
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// a minimal websocket client (RFC 6455) - enough to talk to the Chrome DevTools,
+// which only sends unfragmented or continued text frames, pings and close frames
+
+const websocketGuid = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+type websocketConn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	writeLock sync.Mutex
+}
+
+func dialWebsocket(wsUrl string) (*websocketConn, error) {
+	parsedUrl, err := url.Parse(wsUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsedUrl.Scheme != "ws" {
+		return nil, fmt.Errorf("unsupported websocket url: %s", wsUrl)
+	}
+
+	conn, err := net.DialTimeout("tcp", parsedUrl.Host, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes := make([]byte, 16)
+	_, err = rand.Read(keyBytes)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	handshake := fmt.Sprintf("GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n\r\n", parsedUrl.RequestURI(), parsedUrl.Host, key)
+	_, err = conn.Write([]byte(handshake))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, &http.Request{Method: "GET"})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, fmt.Errorf("websocket handshake failed with status %d", resp.StatusCode)
+	}
+
+	acceptHash := sha1.Sum([]byte(key + websocketGuid))
+	if resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(acceptHash[:]) {
+		conn.Close()
+		return nil, errors.New("websocket handshake failed: invalid Sec-WebSocket-Accept")
+	}
+
+	return &websocketConn{conn: conn, reader: reader}, nil
+}
+
+// writeMessage sends a single text frame - client frames always have to be masked
+func (ws *websocketConn) writeMessage(message []byte) error {
+	return ws.writeFrame(wsOpText, message)
+}
+
+func (ws *websocketConn) writeFrame(opcode byte, payload []byte) error {
+	ws.writeLock.Lock()
+	defer ws.writeLock.Unlock()
+
+	header := []byte{0x80 | opcode}
+
+	length := len(payload)
+	switch {
+	case length < 126:
+		header = append(header, 0x80|byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 0x80|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header = append(header, 0x80|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	mask := make([]byte, 4)
+	_, err := rand.Read(mask)
+	if err != nil {
+		return err
+	}
+	header = append(header, mask...)
+
+	masked := make([]byte, length)
+	for i, b := range payload {
+		masked[i] = b ^ mask[i%4]
+	}
+
+	_, err = ws.conn.Write(append(header, masked...))
+	return err
+}
+
+// readMessage returns the next text or binary message, answering pings on the way
+func (ws *websocketConn) readMessage() ([]byte, error) {
+	var message []byte
+
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsOpPing:
+			err = ws.writeFrame(wsOpPong, payload)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			return nil, io.EOF
+		}
+
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+func (ws *websocketConn) readFrame() (bool, byte, []byte, error) {
+	header := make([]byte, 2)
+	_, err := io.ReadFull(ws.reader, header)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	isMasked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		_, err = io.ReadFull(ws.reader, extended)
+		if err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		_, err = io.ReadFull(ws.reader, extended)
+		if err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended)
+	}
+
+	var mask []byte
+	if isMasked {
+		mask = make([]byte, 4)
+		_, err = io.ReadFull(ws.reader, mask)
+		if err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(ws.reader, payload)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	if isMasked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	if opcode != wsOpContinuation && opcode != wsOpText && opcode != wsOpBinary && opcode != wsOpClose && opcode != wsOpPing && opcode != wsOpPong {
+		return false, 0, nil, fmt.Errorf("unsupported websocket opcode: %d", opcode)
+	}
+
+	return fin, opcode, payload, nil
+}
+
+func (ws *websocketConn) close() error {
+	// best effort - the browser might already be gone
+	_ = ws.writeFrame(wsOpClose, []byte{0x03, 0xE8})
+	return ws.conn.Close()
+}
+
+func isClosedConnectionError(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || strings.Contains(err.Error(), "use of closed network connection")
+}
@@ -0,0 +1,333 @@
+## `Actions` module contains a builder for low level input actions
+## like mouse moves, key presses, and scrolling.
+##
+## Each step of the builder is a single "tick" - steps are performed one after another.
+##
+## ```
+## source = browser |> Browser.find_element!(Css("#draggable"))?
+## target = browser |> Browser.find_element!(Css("#drop-zone"))?
+##
+## actions =
+##     Actions.new(Mouse)
+##     |> Actions.move_to_element(source, { x: 0, y: 0 })
+##     |> Actions.pointer_down(Left)
+##     |> Actions.move_to_element(target, { x: 10, y: 10 })
+##     |> Actions.pointer_up(Left)
+##
+## browser |> Actions.perform!(actions)?
+## ```
+module [
+    Actions,
+    PointerType,
+    MouseButton,
+    new,
+    move_to,
+    move_by,
+    move_to_element,
+    pointer_down,
+    pointer_up,
+    click,
+    double_click,
+    key_down,
+    key_up,
+    press_key,
+    pause,
+    scroll,
+    scroll_in_element,
+    perform!,
+    release!,
+]
+
+import Internal exposing [Browser, Element]
+import InternalError
+import EncodeDecode
+import Effect
+import Debug
+import DebugMode
+
+Actions := {
+    pointer_type : PointerType,
+    steps : List ActionStep,
+}
+
+PointerType : [Mouse, Pen, Touch]
+
+MouseButton : [Left, Middle, Right, Back, Forward]
+
+Origin : [Viewport, Pointer, ElementId Str]
+
+ActionStep : [
+    PointerMove { origin : Origin, x : I64, y : I64, duration : U64 },
+    PointerDown MouseButton,
+    PointerUp MouseButton,
+    KeyDown Str,
+    KeyUp Str,
+    Scroll { origin : Origin, x : I64, y : I64, delta_x : I64, delta_y : I64 },
+    Pause U64,
+]
+
+## Create an empty `Actions` builder using a given pointer type.
+##
+## ```
+## actions = Actions.new(Mouse)
+## ```
+new : PointerType -> Actions
+new = |pointer_type|
+    @Actions({ pointer_type, steps: [] })
+
+## Move the pointer to a position relative to the top left corner of the viewport.
+##
+## ```
+## actions = Actions.new(Mouse) |> Actions.move_to({ x: 100, y: 200 })
+## ```
+move_to : Actions, { x : I64, y : I64 } -> Actions
+move_to = |actions, { x, y }|
+    actions |> add_step(PointerMove({ origin: Viewport, x, y, duration: 0 }))
+
+## Move the pointer by an offset from its current position.
+##
+## ```
+## actions = Actions.new(Mouse) |> Actions.move_by({ x: 10, y: -10 })
+## ```
+move_by : Actions, { x : I64, y : I64 } -> Actions
+move_by = |actions, { x, y }|
+    actions |> add_step(PointerMove({ origin: Pointer, x, y, duration: 0 }))
+
+## Move the pointer to an `Element` - the offset is relative to the center of the `Element`.
+##
+## ```
+## button = browser |> Browser.find_element!(Css("#submit-button"))?
+## actions = Actions.new(Mouse) |> Actions.move_to_element(button, { x: 0, y: 0 })
+## ```
+move_to_element : Actions, Element, { x : I64, y : I64 } -> Actions
+move_to_element = |actions, element, { x, y }|
+    { element_id } = Internal.unpack_element_data(element)
+    actions |> add_step(PointerMove({ origin: ElementId(element_id), x, y, duration: 0 }))
+
+## Press a pointer button.
+##
+## ```
+## actions = Actions.new(Mouse) |> Actions.pointer_down(Left)
+## ```
+pointer_down : Actions, MouseButton -> Actions
+pointer_down = |actions, button|
+    actions |> add_step(PointerDown(button))
+
+## Release a pointer button.
+##
+## ```
+## actions = Actions.new(Mouse) |> Actions.pointer_up(Left)
+## ```
+pointer_up : Actions, MouseButton -> Actions
+pointer_up = |actions, button|
+    actions |> add_step(PointerUp(button))
+
+## Press and release a pointer button.
+##
+## ```
+## actions = Actions.new(Mouse) |> Actions.click(Right)
+## ```
+click : Actions, MouseButton -> Actions
+click = |actions, button|
+    actions |> pointer_down(button) |> pointer_up(button)
+
+## Press and release a pointer button twice.
+##
+## ```
+## actions = Actions.new(Mouse) |> Actions.double_click(Left)
+## ```
+double_click : Actions, MouseButton -> Actions
+double_click = |actions, button|
+    actions |> click(button) |> click(button)
+
+## Press a key.
+##
+## Accepts a single character or a special key, like `{ctrl}`, `{shift}`, `{tab}`, or `{enter}` - see `Element.input_text!`.
+##
+## ```
+## # select all with ctrl+a
+## actions =
+##     Actions.new(Mouse)
+##     |> Actions.key_down("{ctrl}")
+##     |> Actions.press_key("a")
+##     |> Actions.key_up("{ctrl}")
+## ```
+key_down : Actions, Str -> Actions
+key_down = |actions, key|
+    actions |> add_step(KeyDown(key))
+
+## Release a key.
+##
+## ```
+## actions = Actions.new(Mouse) |> Actions.key_up("{shift}")
+## ```
+key_up : Actions, Str -> Actions
+key_up = |actions, key|
+    actions |> add_step(KeyUp(key))
+
+## Press and release a key.
+##
+## ```
+## actions = Actions.new(Mouse) |> Actions.press_key("{enter}")
+## ```
+press_key : Actions, Str -> Actions
+press_key = |actions, key|
+    actions |> key_down(key) |> key_up(key)
+
+## Wait for a given number of milliseconds.
+##
+## ```
+## actions = Actions.new(Mouse) |> Actions.pause(500)
+## ```
+pause : Actions, U64 -> Actions
+pause = |actions, duration|
+    actions |> add_step(Pause(duration))
+
+## Scroll by a given delta, starting at a position relative to the top left corner of the viewport.
+##
+## ```
+## actions = Actions.new(Mouse) |> Actions.scroll({ x: 0, y: 0, delta_x: 0, delta_y: 500 })
+## ```
+scroll : Actions, { x : I64, y : I64, delta_x : I64, delta_y : I64 } -> Actions
+scroll = |actions, { x, y, delta_x, delta_y }|
+    actions |> add_step(Scroll({ origin: Viewport, x, y, delta_x, delta_y }))
+
+## Scroll by a given delta, starting at the center of an `Element`.
+##
+## When the `Element` is outside of the viewport, it will be scrolled into view first.
+##
+## ```
+## list = browser |> Browser.find_element!(Css("#long-list"))?
+## actions = Actions.new(Mouse) |> Actions.scroll_in_element(list, { delta_x: 0, delta_y: 200 })
+## ```
+scroll_in_element : Actions, Element, { delta_x : I64, delta_y : I64 } -> Actions
+scroll_in_element = |actions, element, { delta_x, delta_y }|
+    { element_id } = Internal.unpack_element_data(element)
+    actions |> add_step(Scroll({ origin: ElementId(element_id), x: 0, y: 0, delta_x, delta_y }))
+
+## Perform all steps of the `Actions` in the `Browser`.
+##
+## Pressed keys and buttons stay pressed after `perform!` - use `release!` to release them.
+##
+## ```
+## actions = Actions.new(Mouse) |> Actions.move_to({ x: 100, y: 100 }) |> Actions.click(Left)
+## browser |> Actions.perform!(actions)?
+## ```
+perform! : Browser, Actions => Result {} [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, ElementNotInteractable Str, ElementClickIntercepted Str]
+perform! = |browser, actions|
+    { session_id } = Internal.unpack_browser_data(browser)
+    @Actions({ steps }) = actions
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Performing actions: ${steps |> List.len |> Num.to_str} steps"),
+    )
+
+    Effect.perform_actions!(session_id, actions |> to_json) |> Result.map_err(InternalError.handle_interaction_error)?
+
+    DebugMode.run_if_debug_mode!(
+        |{}|
+            DebugMode.show_debug_message_in_browser!(session_id, "Perform Actions")?
+            DebugMode.wait!({})
+            Ok({}),
+    )
+
+    Ok({})
+
+## Release all keys and pointer buttons that are currently pressed.
+##
+## ```
+## browser |> Actions.release!()?
+## ```
+release! : Browser => Result {} [WebDriverError Str]
+release! = |browser|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Releasing actions"),
+    )
+
+    Effect.release_actions!(session_id) |> Result.map_err(WebDriverError)
+
+add_step : Actions, ActionStep -> Actions
+add_step = |@Actions({ pointer_type, steps }), step|
+    @Actions({ pointer_type, steps: steps |> List.append(step) })
+
+## Encode `Actions` as a list of W3C input sources.
+##
+## Every source gets an action for every step, so the sources stay in sync -
+## steps that belong to other sources are filled with pauses.
+to_json : Actions -> Str
+to_json = |@Actions({ pointer_type, steps })|
+    pointer_actions = steps |> List.map(pointer_step_to_json) |> Str.join_with(",")
+    key_actions = steps |> List.map(key_step_to_json) |> Str.join_with(",")
+    wheel_actions = steps |> List.map(wheel_step_to_json) |> Str.join_with(",")
+
+    pointer_source = "{\"type\":\"pointer\",\"id\":\"r2e-pointer\",\"parameters\":{\"pointerType\":\"${pointer_type_to_str(pointer_type)}\"},\"actions\":[${pointer_actions}]}"
+    key_source = "{\"type\":\"key\",\"id\":\"r2e-keyboard\",\"actions\":[${key_actions}]}"
+    wheel_source = "{\"type\":\"wheel\",\"id\":\"r2e-wheel\",\"actions\":[${wheel_actions}]}"
+
+    "[${pointer_source},${key_source},${wheel_source}]"
+
+pointer_step_to_json : ActionStep -> Str
+pointer_step_to_json = |step|
+    when step is
+        PointerMove({ origin, x, y, duration }) ->
+            "{\"type\":\"pointerMove\",\"origin\":${origin_to_json(origin)},\"x\":${x |> Num.to_str},\"y\":${y |> Num.to_str},\"duration\":${duration |> Num.to_str}}"
+
+        PointerDown(button) -> "{\"type\":\"pointerDown\",\"button\":${button_to_str(button)}}"
+        PointerUp(button) -> "{\"type\":\"pointerUp\",\"button\":${button_to_str(button)}}"
+        other -> pause_for_step(other)
+
+key_step_to_json : ActionStep -> Str
+key_step_to_json = |step|
+    when step is
+        KeyDown(key) -> "{\"type\":\"keyDown\",\"value\":${EncodeDecode.encode_json_string(key)}}"
+        KeyUp(key) -> "{\"type\":\"keyUp\",\"value\":${EncodeDecode.encode_json_string(key)}}"
+        other -> pause_for_step(other)
+
+wheel_step_to_json : ActionStep -> Str
+wheel_step_to_json = |step|
+    when step is
+        Scroll({ origin, x, y, delta_x, delta_y }) ->
+            "{\"type\":\"scroll\",\"origin\":${origin_to_json(origin)},\"x\":${x |> Num.to_str},\"y\":${y |> Num.to_str},\"deltaX\":${delta_x |> Num.to_str},\"deltaY\":${delta_y |> Num.to_str}}"
+
+        other -> pause_for_step(other)
+
+pause_for_step : ActionStep -> Str
+pause_for_step = |step|
+    when step is
+        Pause(duration) -> "{\"type\":\"pause\",\"duration\":${duration |> Num.to_str}}"
+        _ -> "{\"type\":\"pause\",\"duration\":0}"
+
+origin_to_json : Origin -> Str
+origin_to_json = |origin|
+    when origin is
+        Viewport -> "\"viewport\""
+        Pointer -> "\"pointer\""
+        ElementId(element_id) -> "{\"element-6066-11e4-a52e-4f735466cecf\":${EncodeDecode.encode_json_string(element_id)}}"
+
+pointer_type_to_str : PointerType -> Str
+pointer_type_to_str = |pointer_type|
+    when pointer_type is
+        Mouse -> "mouse"
+        Pen -> "pen"
+        Touch -> "touch"
+
+button_to_str : MouseButton -> Str
+button_to_str = |button|
+    when button is
+        Left -> "0"
+        Middle -> "1"
+        Right -> "2"
+        Back -> "3"
+        Forward -> "4"
+
+expect
+    json = new(Mouse) |> pointer_down(Left) |> to_json
+    json == "[{\"type\":\"pointer\",\"id\":\"r2e-pointer\",\"parameters\":{\"pointerType\":\"mouse\"},\"actions\":[{\"type\":\"pointerDown\",\"button\":0}]},{\"type\":\"key\",\"id\":\"r2e-keyboard\",\"actions\":[{\"type\":\"pause\",\"duration\":0}]},{\"type\":\"wheel\",\"id\":\"r2e-wheel\",\"actions\":[{\"type\":\"pause\",\"duration\":0}]}]"
+
+expect
+    json = new(Touch) |> pause(100) |> to_json
+    json |> Str.contains("\"pointerType\":\"touch\"") and json |> Str.contains("{\"type\":\"pause\",\"duration\":100}")
@@ -14,10 +14,16 @@ module [
     should_be_lesser_than,
     should_have_length,
     fail_with,
+    file_downloaded!,
     # element
     element_should_have_text!,
     element_should_have_value!,
     element_should_be_visible!,
+    element_should_be_enabled!,
+    element_should_be_disabled!,
+    element_should_be_focused!,
+    element_should_have_accessible_role!,
+    element_should_have_accessible_name!,
 ]
 
 import Internal exposing [Element, Browser]
@@ -26,6 +32,7 @@ import DebugMode
 import Utils
 import Browser
 import InternalElement
+import Effect
 
 ## Checks if the value of __actual__ is equal to the __expected__.
 ##
@@ -193,6 +200,32 @@ title_should_be! = |browser, expected|
                 Err(AssertionError("Expected the page title to be \"${expected}\", but got \"${actual}\" (waited for ${assert_timeout |> Num.to_str}ms)")),
     )
 
+## Checks if a file matching the name pattern was downloaded in this test.
+##
+## The pattern is a glob - `*` matches any characters and `?` matches a single character.
+##
+## This function will wait for the download to finish,
+## for the **assert_timeout** specified in test options - default: 3s.
+## ```
+## export_button |> Element.click!()?
+## browser |> Assert.file_downloaded!("report-*.csv")
+## ```
+file_downloaded! : Browser, Str => Result {} [AssertionError Str, WebDriverError Str]
+file_downloaded! = |browser, name_pattern|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Assert: Waiting for a download matching \"${name_pattern}\""),
+    )
+
+    assert_timeout = Utils.get_assert_timeout!({})
+
+    when Effect.browser_wait_for_download!(session_id, name_pattern, assert_timeout |> Num.to_i64) is
+        Ok(_) -> Ok({})
+        Err(err) if err |> Str.starts_with("Timeout::") -> Err(AssertionError((err |> Str.drop_prefix("Timeout::"))))
+        Err(err) -> Err(WebDriverError(err))
+
 ## Fails with given error message.
 ##
 ## ```
@@ -245,7 +278,7 @@ pluralize = |count, singular, plural|
 ## # check if button has text "Submit"
 ## button |> Assert.element_should_have_text!("Submit")
 ## ```
-element_should_have_text! : Element, Str => Result {} [AssertionError Str, ElementNotFound Str, WebDriverError Str]
+element_should_have_text! : Element, Str => Result {} [AssertionError Str, ElementNotFound Str, StaleElementReference Str, WebDriverError Str]
 element_should_have_text! = |element, expected_text|
     { selector_text } = Internal.unpack_element_data(element)
 
@@ -301,7 +334,7 @@ loop! = |callback!|
 ## # check if input has value "fake-username"
 ## input |> Assert.element_should_have_value!("fake-username")
 ## ```
-element_should_have_value! : Element, Str => Result {} [AssertionError Str, ElementNotFound Str, WebDriverError Str, PropertyTypeError Str]
+element_should_have_value! : Element, Str => Result {} [AssertionError Str, ElementNotFound Str, StaleElementReference Str, WebDriverError Str, PropertyTypeError Str]
 element_should_have_value! = |element, expected_value|
     { selector_text } = Internal.unpack_element_data(element)
 
@@ -334,7 +367,7 @@ element_should_have_value! = |element, expected_value|
 ## # check if the error message element is visible
 ## errorMsg |> Assert.element_should_be_visible!()
 ## ```
-element_should_be_visible! : Element => Result {} [AssertionError Str, ElementNotFound Str, WebDriverError Str]
+element_should_be_visible! : Element => Result {} [AssertionError Str, ElementNotFound Str, StaleElementReference Str, WebDriverError Str]
 element_should_be_visible! = |element|
     { selector_text } = Internal.unpack_element_data(element)
 
@@ -355,3 +388,168 @@ element_should_be_visible! = |element|
                 NotVisible ->
                     Err(AssertionError("Expected element ${selector_text} to be visible (waited for ${assert_timeout |> Num.to_str}ms)")),
     )
+
+## Checks if the `Element` is enabled.
+##
+## This function will wait for the `Element` to meet the expectation,
+## for the **assert_timeout** specified in test options - default: 3s.
+##
+## ```
+## # find submit button
+## button = browser |> Browser.find_element!(Css("#submit-button"))?
+## # check if the button can be clicked
+## button |> Assert.element_should_be_enabled!()
+## ```
+element_should_be_enabled! : Element => Result {} [AssertionError Str, ElementNotFound Str, StaleElementReference Str, WebDriverError Str]
+element_should_be_enabled! = |element|
+    { selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Assert: Waiting for element ${selector_text} to be enabled"),
+    )
+
+    assert_timeout = Utils.get_assert_timeout!({})
+
+    try_for!(
+        assert_timeout,
+        |{}|
+            is_enabled = element |> InternalElement.is_enabled!?
+
+            when is_enabled is
+                Enabled -> Ok({})
+                Disabled ->
+                    Err(AssertionError("Expected element ${selector_text} to be enabled (waited for ${assert_timeout |> Num.to_str}ms)")),
+    )
+
+## Checks if the `Element` is disabled.
+##
+## This function will wait for the `Element` to meet the expectation,
+## for the **assert_timeout** specified in test options - default: 3s.
+##
+## ```
+## # find submit button
+## button = browser |> Browser.find_element!(Css("#submit-button"))?
+## # check if the button is blocked until the form is valid
+## button |> Assert.element_should_be_disabled!()
+## ```
+element_should_be_disabled! : Element => Result {} [AssertionError Str, ElementNotFound Str, StaleElementReference Str, WebDriverError Str]
+element_should_be_disabled! = |element|
+    { selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Assert: Waiting for element ${selector_text} to be disabled"),
+    )
+
+    assert_timeout = Utils.get_assert_timeout!({})
+
+    try_for!(
+        assert_timeout,
+        |{}|
+            is_enabled = element |> InternalElement.is_enabled!?
+
+            when is_enabled is
+                Disabled -> Ok({})
+                Enabled ->
+                    Err(AssertionError("Expected element ${selector_text} to be disabled (waited for ${assert_timeout |> Num.to_str}ms)")),
+    )
+
+## Checks if the `Element` has focus in the `Browser`.
+##
+## This function will wait for the `Element` to meet the expectation,
+## for the **assert_timeout** specified in test options - default: 3s.
+##
+## ```
+## # open the dialog
+## browser |> Browser.find_element!(Css("#open-dialog"))? |> Element.click!?
+## # check if the focus moved into the dialog
+## browser |> Browser.find_element!(Css("#dialog-close"))? |> Assert.element_should_be_focused!()
+## ```
+element_should_be_focused! : Element => Result {} [AssertionError Str, ElementNotFound Str, StaleElementReference Str, WebDriverError Str]
+element_should_be_focused! = |element|
+    { selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Assert: Waiting for element ${selector_text} to be focused"),
+    )
+
+    assert_timeout = Utils.get_assert_timeout!({})
+
+    try_for!(
+        assert_timeout,
+        |{}|
+            is_focused = element |> InternalElement.is_focused!?
+
+            when is_focused is
+                Focused -> Ok({})
+                NotFocused ->
+                    Err(AssertionError("Expected element ${selector_text} to be focused (waited for ${assert_timeout |> Num.to_str}ms)")),
+    )
+
+## Checks if the `Element` has the __expected__ ARIA role.
+##
+## This function will wait for the `Element` to meet the expectation,
+## for the **assert_timeout** specified in test options - default: 3s.
+##
+## ```
+## # find menu element
+## menu = browser |> Browser.find_element!(Css("#main-menu"))?
+## # check the role exposed to assistive technologies
+## menu |> Assert.element_should_have_accessible_role!("navigation")
+## ```
+element_should_have_accessible_role! : Element, Str => Result {} [AssertionError Str, ElementNotFound Str, StaleElementReference Str, WebDriverError Str]
+element_should_have_accessible_role! = |element, expected_role|
+    { selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Assert: Waiting for element ${selector_text} to have accessible role: \"${expected_role}\""),
+    )
+
+    assert_timeout = Utils.get_assert_timeout!({})
+
+    try_for!(
+        assert_timeout,
+        |{}|
+            role = element |> InternalElement.get_accessible_role!?
+
+            if expected_role == role then
+                Ok({})
+            else
+                Err(AssertionError("Expected element ${selector_text} to have accessible role \"${expected_role}\", but got \"${role}\" (waited for ${assert_timeout |> Num.to_str}ms)")),
+    )
+
+## Checks if the `Element` has the __expected__ accessible name.
+##
+## This function will wait for the `Element` to meet the expectation,
+## for the **assert_timeout** specified in test options - default: 3s.
+##
+## ```
+## # find icon button
+## close_button = browser |> Browser.find_element!(Css("#close-dialog"))?
+## # check the name announced by screen readers
+## close_button |> Assert.element_should_have_accessible_name!("Close")
+## ```
+element_should_have_accessible_name! : Element, Str => Result {} [AssertionError Str, ElementNotFound Str, StaleElementReference Str, WebDriverError Str]
+element_should_have_accessible_name! = |element, expected_name|
+    { selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Assert: Waiting for element ${selector_text} to have accessible name: \"${expected_name}\""),
+    )
+
+    assert_timeout = Utils.get_assert_timeout!({})
+
+    try_for!(
+        assert_timeout,
+        |{}|
+            name = element |> InternalElement.get_accessible_name!?
+
+            if expected_name == name then
+                Ok({})
+            else
+                Err(AssertionError("Expected element ${selector_text} to have accessible name \"${expected_name}\", but got \"${name}\" (waited for ${assert_timeout |> Num.to_str}ms)")),
+    )
@@ -28,11 +28,15 @@ reporter = Reporting.create_reporter(
         [{ file_path: "index.html", content: html_str }],
 )
 
-result_to_html = |{ name, result, duration, screenshot, logs, type }|
+result_to_html = |{ name, result, duration, screenshot, logs, console_logs, project, type }|
+    name_with_project =
+        when project is
+            NoProject -> name
+            Project(project_name) -> "[${project_name}] ${name}"
     safe_name =
         when type is
-            FinalResult -> name |> html_encode
-            Attempt -> "${name} (attempt)" |> html_encode
+            FinalResult -> name_with_project |> html_encode
+            Attempt -> "${name_with_project} (attempt)" |> html_encode
     is_ok = result |> Result.is_ok
     class =
         when type is
@@ -41,7 +45,7 @@ result_to_html = |{ name, result, duration, screenshot, logs, type }|
 
             Attempt ->
                 "warning"
-    test_details = get_test_details(result, screenshot, logs)
+    test_details = get_test_details(result, screenshot, logs, console_logs)
     test_duration = (Num.to_frac(duration)) / 1000 |> frac_to_str
 
     """
@@ -54,9 +58,9 @@ result_to_html = |{ name, result, duration, screenshot, logs, type }|
     </li>
     """
 
-get_test_details = |result, screenshot, logs|
+get_test_details = |result, screenshot, logs, console_logs|
     when result is
-        Ok({}) if logs |> List.is_empty ->
+        Ok({}) if logs |> List.is_empty and console_logs |> List.is_empty ->
             """
             <div class="test-details">
                 <div class="block">
@@ -75,6 +79,7 @@ get_test_details = |result, screenshot, logs|
                         ${print_logs(logs)}
                     </div>
                 </div>
+                ${print_console_logs(console_logs)}
             </div>
             """
 
@@ -97,6 +102,7 @@ get_test_details = |result, screenshot, logs|
                         </div>
                     </div>
                 </div>
+                ${print_console_logs(console_logs)}
                 ${optional_screenshot}
             </div>
             """
@@ -119,6 +125,33 @@ print_logs = |logs|
         <ul class="log-list">${items}</ul>
         """
 
+print_console_logs = |console_logs|
+    if console_logs |> List.is_empty then
+        ""
+    else
+        items =
+            console_logs
+            |> List.map(
+                |{ level, source, message }|
+                    (class, level_str) =
+                        when level is
+                            Error -> ("console-error", "error")
+                            Warning -> ("console-warning", "warning")
+                            Info -> ("console-info", "info")
+                            Debug -> ("console-info", "debug")
+                    """
+                    <li class="${class}">[${level_str}] ${source |> html_encode}: ${message |> html_encode}</li>
+                    """,
+            )
+            |> Str.join_with("")
+
+        """
+        <div class="block">
+            <div class="console-title">Browser console</div>
+            <ul class="log-list console-list">${items}</ul>
+        </div>
+        """
+
 is_final_result = |{ type }| type == FinalResult
 
 handle_error = |error_tag|
@@ -274,6 +307,28 @@ get_html = |duration, success_count, error_count, results_content|
         color: var(--error-color);
     }
 
+    .console-title {
+        color: var(--gray);
+        margin-bottom: 0.5em;
+    }
+
+    ul.console-list {
+        font-family: monospace;
+        font-size: 0.9em;
+    }
+
+    li.console-error {
+        color: var(--error-color);
+    }
+
+    li.console-warning {
+        color: var(--warning-color);
+    }
+
+    li.console-info {
+        color: var(--text-color);
+    }
+
     .test-header {
         cursor: pointer;
         display: flex;
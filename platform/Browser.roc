@@ -14,16 +14,43 @@ module [
     try_find_element!,
     find_single_element!,
     find_elements!,
+    get_active_element!,
     take_screenshot_base64!,
-    # printPdfBase64,
+    ConsoleLog,
+    get_console_logs!,
+    print_pdf!,
+    PrintPdfOptions,
     maximize_window!,
     minimize_window!,
     full_screen_window!,
     set_window_rect!,
     get_window_rect!,
+    get_window_handle!,
+    get_window_handles!,
+    switch_to_window!,
+    open_tab!,
+    close_tab!,
+    wait_for_new_window!,
+    wait_for_network_idle!,
+    wait_for_dom_stable!,
+    wait_for_download!,
+    get_downloads!,
+    read_download!,
+    NetworkConditions,
+    set_network_conditions!,
+    use_frame_by_index!,
+    use_frame_by_name!,
+    use_top_level_frame!,
+    switch_to_top_level_frame!,
+    TimeoutsOverride,
+    with_timeouts!,
     execute_js!,
     execute_js_with_output!,
     execute_js_with_args!,
+    execute_js_async!,
+    execute_js_async_with_args!,
+    execute_js_json!,
+    execute_js_for_elements!,
     Cookie,
     CookieExpiry,
     SameSiteOption,
@@ -42,22 +69,29 @@ module [
 import Effect
 import Common.ExecuteJs as ExecuteJs
 import Common.Locator as Locator
+import Common.NetworkConditions as NetworkConditions
 import DebugMode
 import Debug
 import Internal exposing [Browser, Element]
 import InternalError
+import InternalFrame
+import InternalConsole
+import Utils
 
 ## Opens a new `Browser` window.
 ##
 ## Only the browser provided by the test will be closed automatically,
 ## please remember to close the browser windows you open manually.
 ##
+## This starts a new `Browser` session - use `Browser.open_tab!` to open
+## a new tab in the same session (sharing cookies and storage).
+##
 ## ```
 ## newBrowser = Browser.open_new_window!({})?
 ## ...
 ## newBrowser |> Browser.close_window!()?
 ## ```
-open_new_window! : {} => Result Browser [WebDriverError Str]
+open_new_window! : {} => Result Browser [WebDriverError Str, SessionNotCreated Str]
 open_new_window! = |{}|
     DebugMode.run_if_verbose!(
         |{}|
@@ -65,7 +99,7 @@ open_new_window! = |{}|
     )
 
     Effect.start_session!({})
-    |> Result.map_err(WebDriverError)
+    |> Result.map_err(InternalError.handle_session_error)
     |> Result.map_ok(
         |session_id|
             Internal.pack_browser_data({ session_id }),
@@ -79,7 +113,7 @@ open_new_window! = |{}|
 ##     browser2 |> Browser.navigate_to!("https://www.roc-lang.org/")
 ## )
 ## ```
-open_new_window_with_cleanup! : (Browser => Result val [WebDriverError Str]err) => Result val [WebDriverError Str]err
+open_new_window_with_cleanup! : (Browser => Result val [WebDriverError Str, SessionNotCreated Str]err) => Result val [WebDriverError Str, SessionNotCreated Str]err
 open_new_window_with_cleanup! = |callback!|
     browser = open_new_window!({})?
     result = callback!(browser)
@@ -113,7 +147,7 @@ close_window! = |browser|
 ## # open google.com
 ## browser |> Browser.navigate_to!("http://google.com")?
 ## ```
-navigate_to! : Browser, Str => Result {} [WebDriverError Str]
+navigate_to! : Browser, Str => Result {} [WebDriverError Str, Timeout Str, UnexpectedAlertOpen Str]
 navigate_to! = |browser, url|
     { session_id } = Internal.unpack_browser_data(browser)
 
@@ -122,7 +156,7 @@ navigate_to! = |browser, url|
             Debug.print_line!("Navigating to: ${url}"),
     )
 
-    Effect.browser_navigate_to!(session_id, url) |> Result.map_err(WebDriverError)?
+    Effect.browser_navigate_to!(session_id, url) |> Result.map_err(InternalError.handle_navigation_error)?
 
     DebugMode.run_if_debug_mode!(
         |{}|
@@ -205,7 +239,7 @@ Locator : Locator.Locator
 ## # find the html element with an attribute [data-testid="my-element"]
 ## button = browser |> Browser.find_element!(TestId("my-element"))?
 ## ```
-find_element! : Browser, Locator => Result Element [WebDriverError Str, ElementNotFound Str]
+find_element! : Browser, Locator => Result Element [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, InvalidSelector Str]
 find_element! = |browser, locator|
     { session_id } = Internal.unpack_browser_data(browser)
     (using, value) = Locator.get_locator(locator)
@@ -217,7 +251,7 @@ find_element! = |browser, locator|
             Debug.print_line!("Searching for element: ${selector_text}"),
     )
 
-    element_id = Effect.browser_find_element!(session_id, using, value) |> Result.map_err(InternalError.handle_element_error)?
+    element_id = Effect.browser_find_element!(session_id, using, value) |> Result.map_err(InternalError.handle_find_error)?
 
     DebugMode.run_if_verbose!(
         |{}|
@@ -227,12 +261,12 @@ find_element! = |browser, locator|
     DebugMode.run_if_debug_mode!(
         |{}|
             DebugMode.show_debug_message_in_browser!(session_id, "Find Element ${selector_text}")?
-            DebugMode.flash_elements!(session_id, locator, Single)?
+            DebugMode.flash_elements!(session_id, Locator.to_element_locator(locator), Single)?
             DebugMode.wait!({})
             Ok({}),
     )
 
-    Internal.pack_element_data({ session_id, element_id, selector_text, locator }) |> Ok
+    Internal.pack_element_data({ session_id, element_id, selector_text, locator: Locator.to_element_locator(locator) }) |> Ok
 
 ## Find an `Element` in the `Browser`.
 ##
@@ -253,7 +287,7 @@ find_element! = |browser, locator|
 ##         button_text = el |> Element.get_text!
 ##         Stdout.line!("Button found with text: $(button_text)")
 ## ```
-try_find_element! : Browser, Locator => Result [Found Element, NotFound] [WebDriverError Str, ElementNotFound Str]
+try_find_element! : Browser, Locator => Result [Found Element, NotFound] [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, InvalidSelector Str]
 try_find_element! = |browser, locator|
     find_element!(browser, locator)
     |> Result.map_ok(Found)
@@ -276,7 +310,7 @@ try_find_element! = |browser, locator|
 ## ```
 ## button = browser |> Browser.find_single_element!(Css("#submit-button"))?
 ## ```
-find_single_element! : Browser, Locator => Result Element [AssertionError Str, ElementNotFound Str, WebDriverError Str]
+find_single_element! : Browser, Locator => Result Element [AssertionError Str, ElementNotFound Str, StaleElementReference Str, InvalidSelector Str, WebDriverError Str]
 find_single_element! = |browser, locator|
     elements = find_elements!(browser, locator)?
     when elements |> List.len is
@@ -304,7 +338,7 @@ find_single_element! = |browser, locator|
 ## listItems = browser |> Browser.find_elements!(Css("#my-list li"))?
 ## ```
 ##
-find_elements! : Browser, Locator => Result (List Element) [WebDriverError Str, ElementNotFound Str]
+find_elements! : Browser, Locator => Result (List Element) [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, InvalidSelector Str]
 find_elements! = |browser, locator|
     { session_id } = Internal.unpack_browser_data(browser)
     (using, value) = Locator.get_locator(locator)
@@ -316,7 +350,7 @@ find_elements! = |browser, locator|
             Debug.print_line!("Searching for elements: ${selector_text}"),
     )
 
-    result = Effect.browser_find_elements!(session_id, using, value) |> Result.map_err(InternalError.handle_element_error)
+    result = Effect.browser_find_elements!(session_id, using, value) |> Result.map_err(InternalError.handle_find_error)
 
     when result is
         Ok(element_ids) ->
@@ -331,7 +365,7 @@ find_elements! = |browser, locator|
                         Ok({})
                     else
                         DebugMode.show_debug_message_in_browser!(session_id, "Find Elements ${selector_text}")?
-                        DebugMode.flash_elements!(session_id, locator, All)?
+                        DebugMode.flash_elements!(session_id, Locator.to_element_locator(locator), All)?
                         DebugMode.wait!({})
                         Ok({}),
             )
@@ -339,13 +373,73 @@ find_elements! = |browser, locator|
             element_ids
             |> List.map(
                 |element_id|
-                    Internal.pack_element_data({ session_id, element_id, selector_text, locator }),
+                    Internal.pack_element_data({ session_id, element_id, selector_text, locator: Locator.to_element_locator(locator) }),
             )
             |> Ok
 
         Err(ElementNotFound(_)) -> Ok([])
         Err(err) -> Err(err)
 
+## Get the `Element` that currently has focus in the `Browser`.
+##
+## When nothing is focused, browsers return the document `<body>`.
+##
+## ```
+## # focus the username input
+## browser |> Browser.find_element!(Css("#username"))? |> Element.click!?
+## # get the focused element
+## active = browser |> Browser.get_active_element!?
+## ```
+get_active_element! : Browser => Result Element [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
+get_active_element! = |browser|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    selector_text = "<active element>"
+    locator = Css(":focus")
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Getting active element"),
+    )
+
+    element_id = Effect.browser_get_active_element!(session_id) |> Result.map_err(InternalError.handle_element_error)?
+
+    Internal.pack_element_data({ session_id, element_id, selector_text, locator }) |> Ok
+
+ConsoleLog : InternalConsole.ConsoleLog
+
+## Get the browser console messages and uncaught JavaScript errors logged since the start of the test.
+##
+## The logs are also attached to the test results and shown in the reports.
+##
+## Console logs are collected only in Chrome - in Firefox this function returns an error.
+##
+## ```
+## ConsoleLog : {
+##     level : [Error, Warning, Info, Debug],
+##     # e.g. "console-api" for console.* calls, "javascript" for uncaught exceptions
+##     source : Str,
+##     message : Str,
+##     # unix time in milliseconds
+##     timestamp : U64,
+## }
+## ```
+## ```
+## logs = browser |> Browser.get_console_logs!?
+## errors = logs |> List.keep_if(|{ level }| level == Error)
+## errors |> Assert.should_have_length(0)
+## ```
+get_console_logs! : Browser => Result (List ConsoleLog) [WebDriverError Str]
+get_console_logs! = |browser|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Getting console logs"),
+    )
+
+    InternalConsole.get_console_logs!(session_id)
+
 ## Take a screenshot of the whole document.
 ##
 ## The result will be a **base64** encoded `Str` representation of a PNG file.
@@ -364,72 +458,54 @@ take_screenshot_base64! = |browser|
 
     Effect.browser_get_screenshot!(session_id) |> Result.map_err(WebDriverError)
 
-# PageOrientation : [Landscape, Portrait]
-#
-# PrintPdfPayload : {
-#     page ? PageDimensions,
-#     margin ? PageMargins,
-#     scale ? F64, # 0.1 - 2.0 - default: 1.0
-#     orientation ? PageOrientation, # default: portrait
-#     shrink_to_fit ? Bool, # default: true
-#     background ? Bool, # default: false
-#     page_ranges ? List Str, # default []
-# }
-#
-# PageDimensions : {
-#     width : F64, # default: 21.59 cm
-#     height : F64, # default: 27.94 cm
-# }
-#
-# PageMargins : {
-#     top : F64, # default: 1 cm
-#     bottom : F64, # default: 1 cm
-#     left : F64, # default: 1 cm
-#     right : F64, # default: 1 cm
-# }
-
-## Print current page to PDF.
-##
-## The result will be **base64** encoded `Str`.
+PrintPdfOptions : {
+    page ?? { width : F64, height : F64 },
+    margin ?? { top : F64, bottom : F64, left : F64, right : F64 },
+    scale ?? F64,
+    orientation ?? [Landscape, Portrait],
+    shrink_to_fit ?? Bool,
+    background ?? Bool,
+    page_ranges ?? List Str,
+}
+
+## Print current page to PDF and save it in the results directory.
+##
+## The file is saved to `<results_dir_name>/pdf/<file_name>` - the path of the saved file is returned.
+## The `file_name` cannot contain directories.
 ##
 ## All options are optional, with defaults:
 ## ```
-## PageOrientation : [Landscape, Portrait]
-##
-## PrintPdfPayload : {
-##     page ? PageDimensions,
-##     margin ? PageMargins,
-##     scale ? F64, # 0.1 - 2.0 - default: 1.0
-##     orientation ? PageOrientation, # default: portrait
-##     shrink_to_fit ? Bool, # default: true
-##     background ? Bool, # default: false
-##     page_ranges ? List Str, # default []
-## }
-##
-## PageDimensions : {
-##     width : F64, # default: 21.59 cm
-##     height : F64, # default: 27.94 cm
-## }
-##
-## PageMargins : {
-##     top : F64, # default: 1 cm
-##     bottom : F64, # default: 1 cm
-##     left : F64, # default: 1 cm
-##     right : F64, # default: 1 cm
+## PrintPdfOptions : {
+##     page ?? { width : F64, height : F64 }, # default: { width: 21.59, height: 27.94 } cm
+##     margin ?? { top : F64, bottom : F64, left : F64, right : F64 }, # default: 1 cm on each side
+##     scale ?? F64, # 0.1 - 2.0 - default: 1.0
+##     orientation ?? [Landscape, Portrait], # default: Portrait
+##     shrink_to_fit ?? Bool, # default: true
+##     background ?? Bool, # default: false
+##     page_ranges ?? List Str, # e.g. ["1-3", "5"] - default: [] (all pages)
 ## }
 ## ```
 ## ```
-## base64_pdf_str = browser |> Browser.print_pdf_base64!({})?
+## pdf_path = browser |> Browser.print_pdf!("invoice.pdf", {})?
+##
+## browser |> Browser.print_pdf!("invoice-landscape.pdf", { orientation: Landscape, background: Bool.true, page_ranges: ["1"] })?
 ## ```
-# print_pdf_base64 : Browser, PrintPdfPayload -> Task.Task Str [WebDriverError Str]
-# print_pdf_base64 = \browser, { scale ? 1.0f64, orientation ? Portrait, shrinkToFit ? Bool.true, background ? Bool.false, page ? { width: 21.59f64, height: 27.94f64 }, margin ? { top: 1.0f64, bottom: 1.0f64, left: 1.0f64, right: 1.0f64 }, pageRanges ? [] } ->
-#     { sessionId } = Internal.unpackBrowserData browser
-#
-#     orientationStr = if orientation == Portrait then "portrait" else "landscape"
-#     shrinkToFitI64 = if shrinkToFit then 1 else 0
-#     backgroundI64 = if background then 1 else 0
-#
-#     Effect.browserGetPdf sessionId page.width page.height margin.top margin.bottom margin.left margin.right scale orientationStr shrinkToFitI64 backgroundI64 pageRanges |> Task.mapErr WebDriverError
+print_pdf! : Browser, Str, PrintPdfOptions => Result Str [WebDriverError Str]
+print_pdf! = |browser, file_name, { page ?? { width: 21.59, height: 27.94 }, margin ?? { top: 1.0, bottom: 1.0, left: 1.0, right: 1.0 }, scale ?? 1.0, orientation ?? Portrait, shrink_to_fit ?? Bool.true, background ?? Bool.false, page_ranges ?? [] }|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Printing page to PDF: ${file_name}"),
+    )
+
+    orientation_str = if orientation == Portrait then "portrait" else "landscape"
+    shrink_to_fit_i64 = if shrink_to_fit then 1 else 0
+    background_i64 = if background then 1 else 0
+    page_ranges_str = page_ranges |> Str.join_with(",")
+
+    Effect.browser_print_pdf!(session_id, file_name, page.width, page.height, margin.top, margin.bottom, margin.left, margin.right, scale, orientation_str, shrink_to_fit_i64, background_i64, page_ranges_str)
+    |> Result.map_err(WebDriverError)
 
 WindowRect : {
     x : I64,
@@ -479,7 +555,7 @@ SetWindowRectOptions : [
 ## newRect = browser |> Browser.set_window_rect!(MoveAndResize({ x: 400, y: 600, width: 800, height: 750 }))?
 ## # newRect is { x: 406, y: 627, width: 800, height: 750 }
 ## ```
-set_window_rect! : Browser, SetWindowRectOptions => Result WindowRect [WebDriverError Str]
+set_window_rect! : Browser, SetWindowRectOptions => Result WindowRect [WebDriverError Str, NoSuchWindow Str]
 set_window_rect! = |browser, set_rect_options|
     { session_id } = Internal.unpack_browser_data(browser)
 
@@ -504,7 +580,7 @@ set_window_rect! = |browser, set_rect_options|
                 [x_val, y_val, width_val, height_val] -> { x: x_val, y: y_val, width: width_val |> Num.to_u32, height: height_val |> Num.to_u32 }
                 _ -> crash("the contract with host should not fail"),
     )
-    |> Result.map_err(WebDriverError)
+    |> Result.map_err(InternalError.handle_window_error)
 
 ## Get browser window position and size.
 ##
@@ -520,7 +596,7 @@ set_window_rect! = |browser, set_rect_options|
 ## rect = browser |> Browser.get_window_rect!()?
 ## # rect is { x: 406, y: 627, width: 400, height: 600 }
 ## ```
-get_window_rect! : Browser => Result WindowRect [WebDriverError Str]
+get_window_rect! : Browser => Result WindowRect [WebDriverError Str, NoSuchWindow Str]
 get_window_rect! = |browser|
     { session_id } = Internal.unpack_browser_data(browser)
 
@@ -536,14 +612,14 @@ get_window_rect! = |browser|
                 [x_val, y_val, width_val, height_val] -> { x: x_val, y: y_val, width: width_val |> Num.to_u32, height: height_val |> Num.to_u32 }
                 _ -> crash("the contract with host should not fail"),
     )
-    |> Result.map_err(WebDriverError)
+    |> Result.map_err(InternalError.handle_window_error)
 
 ## Navigate back in the browser history.
 ##
 ## ```
 ## browser |> Browser.navigate_back!()?
 ## ```
-navigate_back! : Browser => Result {} [WebDriverError Str]
+navigate_back! : Browser => Result {} [WebDriverError Str, Timeout Str, UnexpectedAlertOpen Str]
 navigate_back! = |browser|
     { session_id } = Internal.unpack_browser_data(browser)
 
@@ -552,7 +628,7 @@ navigate_back! = |browser|
             Debug.print_line!("Navigating back"),
     )
 
-    Effect.browser_navigate_back!(session_id) |> Result.map_err(WebDriverError)?
+    Effect.browser_navigate_back!(session_id) |> Result.map_err(InternalError.handle_navigation_error)?
 
     DebugMode.run_if_debug_mode!(
         |{}|
@@ -566,7 +642,7 @@ navigate_back! = |browser|
 ## ```
 ## browser |> Browser.navigate_forward!()?
 ## ```
-navigate_forward! : Browser => Result {} [WebDriverError Str]
+navigate_forward! : Browser => Result {} [WebDriverError Str, Timeout Str, UnexpectedAlertOpen Str]
 navigate_forward! = |browser|
     { session_id } = Internal.unpack_browser_data(browser)
 
@@ -575,7 +651,7 @@ navigate_forward! = |browser|
             Debug.print_line!("Navigating froward"),
     )
 
-    Effect.browser_navigate_forward!(session_id) |> Result.map_err(WebDriverError)?
+    Effect.browser_navigate_forward!(session_id) |> Result.map_err(InternalError.handle_navigation_error)?
 
     DebugMode.run_if_debug_mode!(
         |{}|
@@ -589,7 +665,7 @@ navigate_forward! = |browser|
 ## ```
 ## browser |> Browser.reload_page!()?
 ## ```
-reload_page! : Browser => Result {} [WebDriverError Str]
+reload_page! : Browser => Result {} [WebDriverError Str, Timeout Str, UnexpectedAlertOpen Str]
 reload_page! = |browser|
     { session_id } = Internal.unpack_browser_data(browser)
 
@@ -598,7 +674,7 @@ reload_page! = |browser|
             Debug.print_line!("Reloading page"),
     )
 
-    Effect.browser_reload!(session_id) |> Result.map_err(WebDriverError)?
+    Effect.browser_reload!(session_id) |> Result.map_err(InternalError.handle_navigation_error)?
 
     DebugMode.run_if_debug_mode!(
         |{}|
@@ -614,7 +690,7 @@ reload_page! = |browser|
 ## ```
 ## new_rect = browser |> Browser.maximize_window!()?
 ## ```
-maximize_window! : Browser => Result WindowRect [WebDriverError Str]
+maximize_window! : Browser => Result WindowRect [WebDriverError Str, NoSuchWindow Str]
 maximize_window! = |browser|
     { session_id } = Internal.unpack_browser_data(browser)
 
@@ -630,7 +706,7 @@ maximize_window! = |browser|
                 [x_val, y_val, width_val, height_val] -> { x: x_val, y: y_val, width: width_val |> Num.to_u32, height: height_val |> Num.to_u32 }
                 _ -> crash("the contract with host should not fail"),
     )
-    |> Result.map_err(WebDriverError)
+    |> Result.map_err(InternalError.handle_window_error)
 
 ## Minimize the `Browser` window.
 ##
@@ -639,7 +715,7 @@ maximize_window! = |browser|
 ## ```
 ## new_rect = browser |> Browser.minimize_window!()?
 ## ```
-minimize_window! : Browser => Result WindowRect [WebDriverError Str]
+minimize_window! : Browser => Result WindowRect [WebDriverError Str, NoSuchWindow Str]
 minimize_window! = |browser|
     { session_id } = Internal.unpack_browser_data(browser)
 
@@ -655,7 +731,7 @@ minimize_window! = |browser|
                 [x_val, y_val, width_val, height_val] -> { x: x_val, y: y_val, width: width_val |> Num.to_u32, height: height_val |> Num.to_u32 }
                 _ -> crash("the contract with host should not fail"),
     )
-    |> Result.map_err(WebDriverError)
+    |> Result.map_err(InternalError.handle_window_error)
 
 ## Make the `Browser` window full screen.
 ##
@@ -664,7 +740,7 @@ minimize_window! = |browser|
 ## ```
 ## new_rect = browser |> Browser.full_screen_window!()?
 ## ```
-full_screen_window! : Browser => Result WindowRect [WebDriverError Str]
+full_screen_window! : Browser => Result WindowRect [WebDriverError Str, NoSuchWindow Str]
 full_screen_window! = |browser|
     { session_id } = Internal.unpack_browser_data(browser)
 
@@ -680,14 +756,482 @@ full_screen_window! = |browser|
                 [x_val, y_val, width_val, height_val] -> { x: x_val, y: y_val, width: width_val |> Num.to_u32, height: height_val |> Num.to_u32 }
                 _ -> crash("the contract with host should not fail"),
     )
-    |> Result.map_err(WebDriverError)
+    |> Result.map_err(InternalError.handle_window_error)
+
+## Get the handle of the current window (or tab).
+##
+## ```
+## main_window = browser |> Browser.get_window_handle!()?
+## ```
+get_window_handle! : Browser => Result Str [WebDriverError Str, NoSuchWindow Str]
+get_window_handle! = |browser|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Getting current window handle"),
+    )
+
+    Effect.browser_get_window_handle!(session_id) |> Result.map_err(InternalError.handle_window_error)
+
+## Get the handles of all windows (and tabs) opened in this `Browser`.
+##
+## ```
+## handles = browser |> Browser.get_window_handles!()?
+## handles |> Assert.should_have_length(2)
+## ```
+get_window_handles! : Browser => Result (List Str) [WebDriverError Str, NoSuchWindow Str]
+get_window_handles! = |browser|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Getting all window handles"),
+    )
+
+    Effect.browser_get_window_handles!(session_id) |> Result.map_err(InternalError.handle_window_error)
+
+## Switch to another window (or tab) - all following commands will be sent to this window.
+##
+## ```
+## main_window = browser |> Browser.get_window_handle!()?
+## # ...
+## browser |> Browser.switch_to_window!(main_window)?
+## ```
+switch_to_window! : Browser, Str => Result {} [WebDriverError Str, NoSuchWindow Str]
+switch_to_window! = |browser, handle|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Switching to window: ${handle}"),
+    )
+
+    Effect.browser_switch_to_window!(session_id, handle) |> Result.map_err(InternalError.handle_window_error)?
+
+    DebugMode.run_if_debug_mode!(
+        |{}|
+            DebugMode.show_debug_message_in_browser!(session_id, "Switch To Window")?
+            DebugMode.wait!({})
+            Ok({}),
+    )
+
+    Ok({})
+
+## Open a new tab and switch to it.
+##
+## Returns the handle of the new tab.
+##
+## ```
+## main_window = browser |> Browser.get_window_handle!()?
+## browser |> Browser.open_tab!()?
+## browser |> Browser.navigate_to!("https://roc-lang.org")?
+## # ...
+## browser |> Browser.close_tab!()?
+## browser |> Browser.switch_to_window!(main_window)?
+## ```
+open_tab! : Browser => Result Str [WebDriverError Str, NoSuchWindow Str]
+open_tab! = |browser|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Opening new tab"),
+    )
+
+    handle = Effect.browser_new_window!(session_id, "tab") |> Result.map_err(InternalError.handle_window_error)?
+    browser |> switch_to_window!(handle)?
+
+    Ok(handle)
+
+## Close the current window (or tab).
+##
+## Returns the handles of the remaining windows -
+## use `Browser.switch_to_window!` to continue in one of them.
+##
+## When the last window is closed, the `Browser` session ends.
+##
+## ```
+## remaining = browser |> Browser.close_tab!()?
+## ```
+close_tab! : Browser => Result (List Str) [WebDriverError Str, NoSuchWindow Str]
+close_tab! = |browser|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Closing current window"),
+    )
+
+    Effect.browser_close_current_window!(session_id) |> Result.map_err(InternalError.handle_window_error)
+
+## Wait for a window (or tab) that is not in the __known_handles__ list - e.g. a popup,
+## or a link with `target="_blank"`.
+##
+## Returns the handle of the new window - this function does not switch to it.
+##
+## This function will wait for the **assert_timeout** specified in test options - default: 3s.
+##
+## ```
+## known_handles = browser |> Browser.get_window_handles!()?
+## login_button |> Element.click!()?
+##
+## popup = browser |> Browser.wait_for_new_window!(known_handles)?
+## browser |> Browser.switch_to_window!(popup)?
+## ```
+wait_for_new_window! : Browser, List Str => Result Str [WebDriverError Str, NoSuchWindow Str, Timeout Str]
+wait_for_new_window! = |browser, known_handles|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Waiting for a new window"),
+    )
+
+    timeout = Utils.get_assert_timeout!({})
+    start_time = Utils.get_time_milis!({})
+
+    wait_for_new_window_loop!(session_id, known_handles, start_time, timeout)
+
+wait_for_new_window_loop! = |session_id, known_handles, start_time, timeout|
+    handles = Effect.browser_get_window_handles!(session_id) |> Result.map_err(InternalError.handle_window_error)?
+
+    when handles |> List.find_first(|handle| known_handles |> List.contains(handle) |> Bool.not) is
+        Ok(handle) -> Ok(handle)
+        Err(NotFound) ->
+            now = Utils.get_time_milis!({})
+            if now - start_time >= timeout then
+                Err(Timeout("No new window was opened (waited for ${timeout |> Num.to_str}ms)"))
+            else
+                Debug.wait!(100)
+                wait_for_new_window_loop!(session_id, known_handles, start_time, timeout)
+
+## Wait until there were no requests in flight for __idle_time__ milliseconds.
+##
+## Useful for single page apps that keep loading data long after
+## the page load finished. Long-lived requests (e.g. `EventSource`)
+## keep the network busy.
+##
+## Only supported in a local Chrome - not with a remote driver (`--driver-url`).
+##
+## This function will wait for the **page_load_timeout** specified in test options - default: 10s.
+##
+## ```
+## browser |> Browser.navigate_to!("http://localhost:3000/dashboard")?
+## browser |> Browser.wait_for_network_idle!(500)?
+## ```
+wait_for_network_idle! : Browser, U64 => Result {} [WebDriverError Str, Timeout Str]
+wait_for_network_idle! = |browser, idle_time|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Waiting for the network to be idle for ${idle_time |> Num.to_str}ms"),
+    )
+
+    Effect.browser_wait_for_network_idle!(session_id, idle_time |> Num.to_i64) |> Result.map_err(InternalError.handle_wait_error)
+
+## Wait until there were no DOM changes for __quiet_time__ milliseconds.
+##
+## The changes are observed with a `MutationObserver` in the current frame.
+##
+## This function will wait for the **page_load_timeout** specified in test options - default: 10s.
+##
+## ```
+## browser |> Browser.navigate_to!("http://localhost:3000/dashboard")?
+## browser |> Browser.wait_for_dom_stable!(300)?
+## ```
+wait_for_dom_stable! : Browser, U64 => Result {} [WebDriverError Str, Timeout Str]
+wait_for_dom_stable! = |browser, quiet_time|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Waiting for the DOM to be stable for ${quiet_time |> Num.to_str}ms"),
+    )
+
+    Effect.browser_wait_for_dom_stable!(session_id, quiet_time |> Num.to_i64) |> Result.map_err(InternalError.handle_wait_error)
+
+## Wait until a file matching the name pattern is downloaded, and no other download is in progress.
+##
+## Every test has its own download directory in `<results_dir_name>/downloads/` - the path
+## of the downloaded file is returned. The pattern is a glob - `*` matches any characters
+## and `?` matches a single character.
+##
+## The "download.default_directory" Chrome pref from the `config` replaces
+## the directory of each test.
+##
+## Only supported with a local browser (not with `--driver-url`).
+##
+## This function will wait for the **page_load_timeout** specified in test options - default: 10s.
+##
+## ```
+## export_button |> Element.click!()?
+##
+## file_path = browser |> Browser.wait_for_download!("report-*.csv")?
+## ```
+wait_for_download! : Browser, Str => Result Str [WebDriverError Str, Timeout Str]
+wait_for_download! = |browser, name_pattern|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Waiting for a download matching: ${name_pattern}"),
+    )
+
+    Effect.browser_wait_for_download!(session_id, name_pattern, -1) |> Result.map_err(InternalError.handle_wait_error)
+
+## Get the names of the files downloaded in this test - the downloads in progress are not included.
+##
+## ```
+## files = browser |> Browser.get_downloads!()?
+## files |> Assert.should_be(["report.csv"])?
+## ```
+get_downloads! : Browser => Result (List Str) [WebDriverError Str]
+get_downloads! = |browser|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    Effect.browser_get_downloads!(session_id) |> Result.map_err(WebDriverError)
+
+## Read the bytes of a file downloaded in this test.
+##
+## ```
+## _ = browser |> Browser.wait_for_download!("report.csv")?
+##
+## content = browser |> Browser.read_download!("report.csv")? |> Str.from_utf8_lossy
+## content |> Assert.should_contain_text("id,name")?
+## ```
+read_download! : Browser, Str => Result (List U8) [WebDriverError Str]
+read_download! = |browser, file_name|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Reading the download: ${file_name}"),
+    )
+
+    Effect.browser_read_download!(session_id, file_name) |> Result.map_err(WebDriverError)
+
+## Network throttling presets (the same as in the Chrome DevTools)
+##
+## `NoThrottling` - the network is not throttled
+##
+## `Offline` - all requests fail
+##
+## `Slow3G` - 2000ms latency, 400 kbit/s download and upload
+##
+## `Fast3G` - 563ms latency, 1440 kbit/s download, 675 kbit/s upload
+##
+## `Custom` - latency in milliseconds, throughput in kbit/s
+NetworkConditions : NetworkConditions.NetworkConditions
+
+## Throttle the network of the `Browser` - e.g. to test the offline mode
+## or the loading states of the page.
+##
+## The conditions stay until the end of the test, or until they are changed again.
+## To throttle whole tests use the `network_conditions` in the config or in `Test.test_with`.
+##
+## Only supported in Chrome - in Firefox it returns a `WebDriverError`.
+##
+## ```
+## browser |> Browser.set_network_conditions!(Offline)?
+## # ... check the offline banner
+## browser |> Browser.set_network_conditions!(NoThrottling)?
+##
+## browser |> Browser.set_network_conditions!(Custom({ latency: 300, download_kbps: 1000, upload_kbps: 500 }))?
+## ```
+set_network_conditions! : Browser, NetworkConditions => Result {} [WebDriverError Str]
+set_network_conditions! = |browser, conditions|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Setting network conditions: ${conditions |> Inspect.to_str}"),
+    )
+
+    Effect.browser_set_network_conditions!(session_id, NetworkConditions.to_json(conditions)) |> Result.map_err(WebDriverError)
+
+## Switch the context to a frame by its index in the current page.
+##
+## Index `0` is the first `<iframe>` (or `<frame>`) in the current context.
+## The callback gets the `Browser` inside that frame, and the previous
+## frame context is restored afterwards - even when the callback failed.
+##
+## ```
+## Browser.use_frame_by_index!(browser, 0, |frame|
+##     span = frame |> Browser.find_element!(Css("#span-inside-frame"))?
+##     span |> Assert.element_should_have_text!("This is inside an iFrame")
+## )
+## ```
+use_frame_by_index! : Browser, U64, (Browser => Result {} _) => Result {} _
+use_frame_by_index! = |browser, index, callback!|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    InternalFrame.use_frame!(
+        session_id,
+        "frame with index ${index |> Num.to_str}",
+        |{}| Effect.switch_to_frame_by_index!(session_id, index |> Num.to_i64) |> Result.map_err(InternalError.handle_frame_error),
+        callback!,
+    )
+
+## Switch the context to a frame by its `name` attribute.
+##
+## Looks for an `<iframe>` (or `<frame>`) with the given `name` in the current context.
+## Calls can be nested to reach frames inside frames.
+##
+## ```
+## Browser.use_frame_by_name!(browser, "payment", |payment|
+##     Browser.use_frame_by_name!(payment, "card-number", |card|
+##         card |> Browser.find_element!(Css("input"))? |> Element.input_text!("4242424242424242")
+##     )
+## )
+## ```
+use_frame_by_name! : Browser, Str, (Browser => Result {} _) => Result {} _
+use_frame_by_name! = |browser, name, callback!|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    escaped_name = css_string_escape(name)
+    selector = "iframe[name=\"${escaped_name}\"], frame[name=\"${escaped_name}\"]"
+
+    InternalFrame.use_frame!(
+        session_id,
+        "frame with name \"${name}\"",
+        |{}|
+            when Effect.browser_find_element!(session_id, "css selector", selector) is
+                Ok(element_id) -> Effect.switch_to_frame_by_element_id!(session_id, element_id) |> Result.map_err(InternalError.handle_frame_error)
+                Err(e) if e |> Str.starts_with("ElementNotFound::") -> Err(NoSuchFrame("no frame with name \"${name}\" in the current context"))
+                Err(e) -> Err(WebDriverError(e)),
+        callback!,
+    )
+
+# escapes the value for a double-quoted CSS string
+css_string_escape : Str -> Str
+css_string_escape = |str|
+    str
+    |> Str.replace_each("\\", "\\\\")
+    |> Str.replace_each("\"", "\\\"")
+
+expect css_string_escape("payment") == "payment"
+expect css_string_escape("a\"b\\c") == "a\\\"b\\\\c"
+
+## Switch the context to the top-level document for the duration of the callback.
+##
+## Useful when code running inside a frame needs to reach the main page.
+## The frame context is restored afterwards.
+##
+## ```
+## Element.use_iframe!(frame_el, |frame|
+##     frame |> Browser.find_element!(Css("#accept"))? |> Element.click!?
+##     Browser.use_top_level_frame!(frame, |page|
+##         page |> Browser.find_element!(Css("#status"))? |> Assert.element_should_have_text!("Accepted")
+##     )
+## )
+## ```
+use_top_level_frame! : Browser, (Browser => Result {} _) => Result {} _
+use_top_level_frame! = |browser, callback!|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    InternalFrame.use_frame!(
+        session_id,
+        "top-level document",
+        |{}| Effect.switch_to_top_level_frame!(session_id) |> Result.map_err(InternalError.handle_frame_error),
+        callback!,
+    )
+
+## Switch the context back to the top-level document.
+##
+## Leaves every frame entered so far - e.g. to recover after a failure
+## in deeply nested frames. Surrounding `use_iframe!` and `use_frame_*!`
+## calls still restore their frame context when their callback returns.
+##
+## ```
+## browser |> Browser.switch_to_top_level_frame!?
+## ```
+switch_to_top_level_frame! : Browser => Result {} [WebDriverError Str, NoSuchFrame Str]
+switch_to_top_level_frame! = |browser|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Switching context to the top-level document"),
+    )
+
+    Effect.switch_to_top_level_frame!(session_id) |> Result.map_err(InternalError.handle_frame_error)
+
+TimeoutsOverride : {
+    element_implicit_timeout ?? [Inherit, Override U64],
+    page_load_timeout ?? [Inherit, Override U64],
+    script_execution_timeout ?? [Inherit, Override U64],
+}
+
+## Change the session timeouts for the duration of the callback.
+##
+## The previous timeouts are restored afterwards - also when the callback failed,
+## in which case the error of the callback is returned.
+## Omitted fields keep their current value.
+##
+## ```
+## TimeoutsOverride : {
+##     element_implicit_timeout ?? [Inherit, Override U64], # default: Inherit
+##     page_load_timeout ?? [Inherit, Override U64], # default: Inherit
+##     script_execution_timeout ?? [Inherit, Override U64], # default: Inherit
+## }
+## ```
+## ```
+## # the report page is slow - give only this navigation 60s
+## Browser.with_timeouts!(browser, { page_load_timeout: Override(60_000) }, |slow_browser|
+##     slow_browser |> Browser.navigate_to!("http://localhost:3000/yearly-report")
+## )?
+## ```
+with_timeouts! : Browser, TimeoutsOverride, (Browser => Result a _) => Result a _
+with_timeouts! = |browser, { element_implicit_timeout ?? Inherit, page_load_timeout ?? Inherit, script_execution_timeout ?? Inherit }, callback!|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    previous = Effect.browser_get_timeouts!(session_id) |> Result.map_err(WebDriverError)?
+
+    (previous_implicit, previous_page_load, previous_script) =
+        when previous is
+            [implicit, page_load, script] -> (implicit, page_load, script)
+            _ -> crash("the contract with host should not fail")
+
+    apply_override = |override, current|
+        when override is
+            Inherit -> current
+            Override(timeout) -> timeout |> Num.to_i64
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Changing session timeouts"),
+    )
+
+    Effect.browser_set_timeouts!(
+        session_id,
+        apply_override(element_implicit_timeout, previous_implicit),
+        apply_override(page_load_timeout, previous_page_load),
+        apply_override(script_execution_timeout, previous_script),
+    )
+    |> Result.map_err(WebDriverError)?
+
+    result = callback!(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Restoring session timeouts"),
+    )
+
+    restore_result = Effect.browser_set_timeouts!(session_id, previous_implicit, previous_page_load, previous_script) |> Result.map_err(WebDriverError)
+
+    # the error of the callback is more useful than the error of restoring the timeouts
+    when result is
+        Ok(value) -> restore_result |> Result.map_ok(|{}| value)
+        Err(err) -> Err(err)
 
 ## Execute JavaScript in the `Browser`.
 ##
 ## ```
 ## browser |> Browser.execute_js!("console.log('wow')")?
 ## ```
-execute_js! : Browser, Str => Result {} [WebDriverError Str, JsReturnTypeError Str] where a implements Decoding
+execute_js! : Browser, Str => Result {} [WebDriverError Str, Timeout Str, JsReturnTypeError Str] where a implements Decoding
 execute_js! = |browser, script|
     DebugMode.run_if_verbose!(
         |{}|
@@ -734,7 +1278,7 @@ execute_js! = |browser, script|
 ## ```
 ##
 ## The function can return a `Promise`.
-execute_js_with_output! : Browser, Str => Result a [WebDriverError Str, JsReturnTypeError Str] where a implements Decoding
+execute_js_with_output! : Browser, Str => Result a [WebDriverError Str, Timeout Str, JsReturnTypeError Str] where a implements Decoding
 execute_js_with_output! = |browser, script|
     DebugMode.run_if_verbose!(
         |{}|
@@ -781,7 +1325,7 @@ JsValue : [String Str, Number F64, Boolean Bool, Null]
 ## ```
 ##
 ## The function can return a `Promise`.
-execute_js_with_args! : Browser, Str, List JsValue => Result a [WebDriverError Str, JsReturnTypeError Str] where a implements Decoding
+execute_js_with_args! : Browser, Str, List JsValue => Result a [WebDriverError Str, Timeout Str, JsReturnTypeError Str] where a implements Decoding
 execute_js_with_args! = |browser, script, arguments|
     DebugMode.run_if_verbose!(
         |{}|
@@ -797,6 +1341,134 @@ execute_js_with_args! = |browser, script, arguments|
 
     Ok(result)
 
+## Execute asynchronous JavaScript in the `Browser` and get the response.
+##
+## The script gets a callback as the last element of the `arguments` array -
+## the result is the value passed to this callback.
+##
+## The `Browser` waits for the callback for the **script_execution_timeout** specified in test options - default: 10s.
+##
+## The output will be casted to expected Roc type - like in `Browser.execute_js_with_output!`.
+##
+## ```
+## response = browser |> Browser.execute_js_async!(
+##     """
+##     const done = arguments[arguments.length - 1];
+##     fetch("/api/health").then((res) => done(res.status));
+##     """,
+## )?
+## response |> Assert.should_be(200)
+## ```
+execute_js_async! : Browser, Str => Result a [WebDriverError Str, Timeout Str, JsReturnTypeError Str] where a implements Decoding
+execute_js_async! = |browser, script|
+    execute_js_async_with_args!(browser, script, [])
+
+## Execute asynchronous JavaScript in the `Browser` with arguments and get the response.
+##
+## The arguments are followed by a callback in the `arguments` array -
+## the result is the value passed to this callback.
+##
+## The `Browser` waits for the callback for the **script_execution_timeout** specified in test options - default: 10s.
+##
+## ```
+## response = browser |> Browser.execute_js_async_with_args!(
+##     """
+##     const [delay, done] = arguments;
+##     setTimeout(() => done("ready"), delay);
+##     """,
+##     [Number(100)],
+## )?
+## response |> Assert.should_be("ready")
+## ```
+execute_js_async_with_args! : Browser, Str, List JsValue => Result a [WebDriverError Str, Timeout Str, JsReturnTypeError Str] where a implements Decoding
+execute_js_async_with_args! = |browser, script, arguments|
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Executing asynchronous JavaScript in the browser"),
+    )
+
+    result = ExecuteJs.execute_js_async!(browser, script, arguments)?
+
+    DebugMode.run_if_debug_mode!(
+        |{}|
+            DebugMode.wait!({}),
+    )
+
+    Ok(result)
+
+## Execute JavaScript in the `Browser` and decode the JSON response.
+##
+## Unlike `Browser.execute_js_with_output!`, this function can decode lists, records, and tuples.
+## Record fields are matched by name - camelCase names from JS will also match snake_case Roc fields.
+##
+## Args can only be used using the `arguments` array in js.
+##
+## ```
+## rows : List { name : Str, unit_price : F64 }
+## rows = browser |> Browser.execute_js_json!(
+##     """
+##     return [...document.querySelectorAll("#products tr")].map((row) => ({
+##       name: row.cells[0].innerText,
+##       unitPrice: Number(row.cells[1].innerText),
+##     }));
+##     """,
+##     [],
+## )?
+## ```
+execute_js_json! : Browser, Str, List JsValue => Result a [WebDriverError Str, Timeout Str, JsReturnTypeError Str] where a implements Decoding
+execute_js_json! = |browser, script, arguments|
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Executing JavaScript in the browser"),
+    )
+
+    result = ExecuteJs.execute_js_json!(browser, script, arguments)?
+
+    DebugMode.run_if_debug_mode!(
+        |{}|
+            DebugMode.wait!({}),
+    )
+
+    Ok(result)
+
+## Execute JavaScript in the `Browser` and get the returned `Elements`.
+##
+## The script can return a single element, a list of elements, or `null`.
+##
+## ```
+## rows = browser |> Browser.execute_js_for_elements!(
+##     "return [...document.querySelectorAll('tr')].filter((row) => row.innerText.includes(arguments[0]));",
+##     [String("Apple")],
+## )?
+## rows |> Assert.should_have_length(2)
+## ```
+execute_js_for_elements! : Browser, Str, List JsValue => Result (List Element) [WebDriverError Str, Timeout Str, JsReturnTypeError Str]
+execute_js_for_elements! = |browser, script, arguments|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Executing JavaScript in the browser to find elements"),
+    )
+
+    element_ids = ExecuteJs.execute_js_element_ids!(browser, script, arguments)?
+
+    DebugMode.run_if_debug_mode!(
+        |{}|
+            DebugMode.wait!({}),
+    )
+
+    element_ids
+    |> List.map_with_index(
+        |element_id, index|
+            selector_text = "<element ${index |> Num.to_str} returned from js>"
+            # there is no selector for elements returned from js
+            locator = JsResult
+
+            Internal.pack_element_data({ session_id, element_id, selector_text, locator }),
+    )
+    |> Ok
+
 # COOKIES
 NewCookie : {
     name : Str,
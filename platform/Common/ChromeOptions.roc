@@ -0,0 +1,52 @@
+module [ChromeOptions, ChromePref, to_json]
+
+import EncodeDecode
+
+## Extra Chrome options
+##
+## `args` - additional command line switches, e.g. "--disable-gpu"
+##
+## `prefs` - Chrome preferences, e.g. "intl.accept_languages" or "profile.default_content_setting_values.notifications"
+##
+## `exclude_switches` - switches that chromedriver passes by default and should not be used, e.g. "enable-automation"
+##
+## `extensions` - paths to unpacked extensions to load
+ChromeOptions : {
+    args : List Str,
+    prefs : List ChromePref,
+    exclude_switches : List Str,
+    extensions : List Str,
+}
+
+ChromePref : { name : Str, value : [String Str, Number F64, Boolean Bool] }
+
+# The JSON passed to the host.
+to_json : ChromeOptions -> Str
+to_json = |{ args, prefs, exclude_switches, extensions }|
+    prefs_str =
+        prefs
+        |> List.map(|{ name, value }| "${EncodeDecode.encode_json_string(name)}:${value |> pref_value_to_json}")
+        |> Str.join_with(",")
+
+    "{\"args\":${args |> str_list_to_json},\"prefs\":{${prefs_str}},\"excludeSwitches\":${exclude_switches |> str_list_to_json},\"extensions\":${extensions |> str_list_to_json}}"
+
+pref_value_to_json = |value|
+    when value is
+        String(str) -> EncodeDecode.encode_json_string(str)
+        Number(num) -> num |> Num.to_str
+        Boolean(bool) -> if bool then "true" else "false"
+
+str_list_to_json = |list|
+    items = list |> List.map(EncodeDecode.encode_json_string) |> Str.join_with(",")
+
+    "[${items}]"
+
+expect to_json({ args: [], prefs: [], exclude_switches: [], extensions: [] }) == "{\"args\":[],\"prefs\":{},\"excludeSwitches\":[],\"extensions\":[]}"
+expect
+    options = {
+        args: ["--disable-gpu"],
+        prefs: [{ name: "download.prompt_for_download", value: Boolean(Bool.false) }, { name: "intl.accept_languages", value: String("de-DE") }],
+        exclude_switches: ["enable-automation"],
+        extensions: ["./my-extension"],
+    }
+    to_json(options) == "{\"args\":[\"--disable-gpu\"],\"prefs\":{\"download.prompt_for_download\":false,\"intl.accept_languages\":\"de-DE\"},\"excludeSwitches\":[\"enable-automation\"],\"extensions\":[\"./my-extension\"]}"
@@ -0,0 +1,63 @@
+module [Device, to_json]
+
+import EncodeDecode
+
+## Device emulation profiles
+##
+## `Desktop` - no emulation, the window size from the config is used
+##
+## `IPhoneSE`, `IPhone14`, `Pixel7`, `IPadMini`, `IPadPro11` - presets with
+## the viewport size (in CSS pixels), pixel ratio, user agent and touch support of the device
+##
+## `Custom` - your own device profile
+Device : [
+    Desktop,
+    IPhoneSE,
+    IPhone14,
+    Pixel7,
+    IPadMini,
+    IPadPro11,
+    Custom DeviceProfile,
+]
+
+DeviceProfile : {
+    width : U64,
+    height : U64,
+    pixel_ratio : F64,
+    user_agent : Str,
+    touch : Bool,
+    mobile : Bool,
+}
+
+iphone_user_agent = "Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Mobile/15E148 Safari/604.1"
+ipad_user_agent = "Mozilla/5.0 (iPad; CPU OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Mobile/15E148 Safari/604.1"
+android_user_agent = "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.0.0 Mobile Safari/537.36"
+
+get_profile : Device -> [Emulated DeviceProfile, NotEmulated]
+get_profile = |device|
+    when device is
+        Desktop -> NotEmulated
+        IPhoneSE -> Emulated({ width: 375, height: 667, pixel_ratio: 2.0, user_agent: iphone_user_agent, touch: Bool.true, mobile: Bool.true })
+        IPhone14 -> Emulated({ width: 390, height: 844, pixel_ratio: 3.0, user_agent: iphone_user_agent, touch: Bool.true, mobile: Bool.true })
+        Pixel7 -> Emulated({ width: 412, height: 915, pixel_ratio: 2.625, user_agent: android_user_agent, touch: Bool.true, mobile: Bool.true })
+        IPadMini -> Emulated({ width: 768, height: 1024, pixel_ratio: 2.0, user_agent: ipad_user_agent, touch: Bool.true, mobile: Bool.true })
+        IPadPro11 -> Emulated({ width: 834, height: 1194, pixel_ratio: 2.0, user_agent: ipad_user_agent, touch: Bool.true, mobile: Bool.true })
+        Custom(profile) -> Emulated(profile)
+
+# The JSON passed to the host - the chromedriver "mobileEmulation" option, or an empty string for no emulation.
+to_json : Device -> Str
+to_json = |device|
+    when get_profile(device) is
+        NotEmulated -> ""
+        Emulated({ width, height, pixel_ratio, user_agent, touch, mobile }) ->
+            metrics = "{\"width\":${width |> Num.to_str},\"height\":${height |> Num.to_str},\"pixelRatio\":${pixel_ratio |> Num.to_str},\"touch\":${touch |> bool_to_str},\"mobile\":${mobile |> bool_to_str}}"
+
+            "{\"deviceMetrics\":${metrics},\"userAgent\":${EncodeDecode.encode_json_string(user_agent)}}"
+
+bool_to_str = |bool|
+    if bool then "true" else "false"
+
+expect to_json(Desktop) == ""
+expect
+    json = to_json(Custom({ width: 100, height: 200, pixel_ratio: 1.5, user_agent: "my agent", touch: Bool.false, mobile: Bool.true }))
+    json == "{\"deviceMetrics\":{\"width\":100,\"height\":200,\"pixelRatio\":1.5,\"touch\":false,\"mobile\":true},\"userAgent\":\"my agent\"}"
@@ -1,33 +1,61 @@
-module [execute_js!, execute_js_with_args!, JsValue]
+module [execute_js!, execute_js_with_args!, execute_js_async!, execute_js_json!, execute_js_element_ids!, JsValue]
 
 import Internal exposing [Browser]
+import InternalError
 import Effect
 import PropertyDecoder
+import JsonDecoder
 import EncodeDecode
 
 JsValue : [String Str, Number F64, Boolean Bool, Null]
 
-execute_js! : Browser, Str => Result a [WebDriverError Str, JsReturnTypeError Str] where a implements Decoding
+execute_js! : Browser, Str => Result a [WebDriverError Str, Timeout Str, JsReturnTypeError Str] where a implements Decoding
 execute_js! = |browser, script|
     { session_id } = Internal.unpack_browser_data(browser)
 
-    result_str = Effect.execute_js!(session_id, script, "[]") |> Result.map_err(|err| WebDriverError(err))?
-    result_utf8 = result_str |> Str.to_utf8
+    Effect.execute_js!(session_id, script, "[]") |> Result.map_err(InternalError.handle_script_error)? |> decode_js_result
+
+execute_js_with_args! : Browser, Str, List JsValue => Result a [WebDriverError Str, Timeout Str, JsReturnTypeError Str] where a implements Decoding
+execute_js_with_args! = |browser, script, arguments|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    arguments_str = arguments |> js_arguments_to_str
+
+    Effect.execute_js!(session_id, script, arguments_str) |> Result.map_err(InternalError.handle_script_error)? |> decode_js_result
+
+execute_js_async! : Browser, Str, List JsValue => Result a [WebDriverError Str, Timeout Str, JsReturnTypeError Str] where a implements Decoding
+execute_js_async! = |browser, script, arguments|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    arguments_str = arguments |> js_arguments_to_str
+
+    Effect.execute_js_async!(session_id, script, arguments_str) |> Result.map_err(InternalError.handle_script_error)? |> decode_js_result
+
+execute_js_json! : Browser, Str, List JsValue => Result a [WebDriverError Str, Timeout Str, JsReturnTypeError Str] where a implements Decoding
+execute_js_json! = |browser, script, arguments|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    arguments_str = arguments |> js_arguments_to_str
+
+    result_str = Effect.execute_js_json!(session_id, script, arguments_str) |> Result.map_err(InternalError.handle_script_error)?
 
     decoded : Result a _
-    decoded = Decode.from_bytes(result_utf8, PropertyDecoder.utf8)
+    decoded = Decode.from_bytes(result_str |> Str.to_utf8, JsonDecoder.json)
 
     when decoded is
         Ok(val) -> Ok(val)
-        Err(_) -> Err(JsReturnTypeError("unsupported return type from js: \"${result_str}\""))
+        Err(_) -> Err(JsReturnTypeError("could not decode the js result: ${result_str}"))
 
-execute_js_with_args! : Browser, Str, List JsValue => Result a [WebDriverError Str, JsReturnTypeError Str] where a implements Decoding
-execute_js_with_args! = |browser, script, arguments|
+execute_js_element_ids! : Browser, Str, List JsValue => Result (List Str) [WebDriverError Str, Timeout Str, JsReturnTypeError Str]
+execute_js_element_ids! = |browser, script, arguments|
     { session_id } = Internal.unpack_browser_data(browser)
 
     arguments_str = arguments |> js_arguments_to_str
 
-    result_str = Effect.execute_js!(session_id, script, arguments_str) |> Result.map_err(WebDriverError)?
+    Effect.execute_js_elements!(session_id, script, arguments_str) |> Result.map_err(InternalError.handle_script_error)
+
+decode_js_result : Str -> Result a [JsReturnTypeError Str]err where a implements Decoding
+decode_js_result = |result_str|
     result_utf8 = result_str |> Str.to_utf8
 
     decoded : Result a _
@@ -1,4 +1,4 @@
-module [Locator, get_locator]
+module [Locator, ElementLocator, get_locator, to_element_locator]
 
 ## Supported locator strategies
 ##
@@ -30,3 +30,22 @@ get_locator = |locator|
         PartialLinkText(text) -> ("partial link text", text)
         # Tag tag -> ("tag name", tag)
         XPath(path) -> ("xpath", path)
+
+# The locator an `Element` was found with - the elements returned from JavaScript have none.
+ElementLocator : [
+    Css Str,
+    TestId Str,
+    XPath Str,
+    LinkText Str,
+    PartialLinkText Str,
+    JsResult,
+]
+
+to_element_locator : Locator -> ElementLocator
+to_element_locator = |locator|
+    when locator is
+        Css(css_selector) -> Css(css_selector)
+        TestId(id) -> TestId(id)
+        XPath(path) -> XPath(path)
+        LinkText(text) -> LinkText(text)
+        PartialLinkText(text) -> PartialLinkText(text)
@@ -0,0 +1,45 @@
+module [NetworkConditions, to_json]
+
+## Network throttling presets (the same as in the Chrome DevTools)
+##
+## `NoThrottling` - the network is not throttled
+##
+## `Offline` - all requests fail
+##
+## `Slow3G` - 2000ms latency, 400 kbit/s download and upload
+##
+## `Fast3G` - 563ms latency, 1440 kbit/s download, 675 kbit/s upload
+##
+## `Custom` - latency in milliseconds, throughput in kbit/s
+NetworkConditions : [
+    NoThrottling,
+    Offline,
+    Slow3G,
+    Fast3G,
+    Custom { latency : U64, download_kbps : U64, upload_kbps : U64 },
+]
+
+# The JSON passed to the host - throughput in bytes per second, -1 disables the throttling.
+to_json : NetworkConditions -> Str
+to_json = |conditions|
+    when conditions is
+        NoThrottling -> conditions_json(Bool.false, 0, -1, -1)
+        Offline -> conditions_json(Bool.true, 0, 0, 0)
+        Slow3G -> conditions_json(Bool.false, 2000, 50_000, 50_000)
+        Fast3G -> conditions_json(Bool.false, 563, 180_000, 84_375)
+        Custom({ latency, download_kbps, upload_kbps }) ->
+            conditions_json(Bool.false, latency |> Num.to_i64, kbps_to_bytes(download_kbps), kbps_to_bytes(upload_kbps))
+
+kbps_to_bytes : U64 -> I64
+kbps_to_bytes = |kbps|
+    kbps * 125 |> Num.to_i64
+
+conditions_json : Bool, I64, I64, I64 -> Str
+conditions_json = |offline, latency, download, upload|
+    offline_str = if offline then "true" else "false"
+
+    "{\"offline\":${offline_str},\"latency\":${latency |> Num.to_str},\"download_throughput\":${download |> Num.to_str},\"upload_throughput\":${upload |> Num.to_str}}"
+
+expect to_json(NoThrottling) == "{\"offline\":false,\"latency\":0,\"download_throughput\":-1,\"upload_throughput\":-1}"
+expect to_json(Offline) == "{\"offline\":true,\"latency\":0,\"download_throughput\":0,\"upload_throughput\":0}"
+expect to_json(Custom({ latency: 100, download_kbps: 8, upload_kbps: 16 })) == "{\"offline\":false,\"latency\":100,\"download_throughput\":1000,\"upload_throughput\":2000}"
@@ -1,7 +1,10 @@
-module [R2EConfiguration, default_config, default_config_with]
+module [R2EConfiguration, Project, ChromeOptions, NetworkConditions, Device, default_config, default_config_with, project, chrome_options]
 
 import InternalReporting exposing [ReporterDefinition]
 import BasicHtmlReporter
+import Common.NetworkConditions as NetworkConditions
+import Common.Device as Device
+import Common.ChromeOptions as ChromeOptions
 
 R2EConfiguration test_error : {
     # the directory name where the results will be stored
@@ -22,6 +25,35 @@ R2EConfiguration test_error : {
     screenshot_on_fail : [Yes, No],
     # number of attempts | Default: 2
     attempts : U64,
+    # browser to run the tests in - the `--browser` CLI flag takes precedence | Default: Chrome
+    browser : [Chrome, Firefox],
+    # should fail a test when the page throws an uncaught exception or logs a console.error? (Chrome only - in Firefox a warning is printed once and the check is skipped) | Default: No
+    fail_on_js_errors : [Yes, No],
+    # regex patterns of js errors that should not fail a test | Default: []
+    js_errors_allow_list : List Str,
+    # network throttling - NoThrottling, Offline, Slow3G, Fast3G or Custom (Chrome only - ignored in Firefox) | Default: NoThrottling
+    network_conditions : NetworkConditions,
+    # device to emulate - Desktop, a preset (e.g. IPhone14, Pixel7, IPadMini) or a Custom profile | Default: Desktop
+    device : Device,
+    # named configuration variants - every test runs once per project, see `Config.project` | Default: []
+    projects : List Project,
+    # extra Chrome args, prefs, excluded switches and unpacked extensions, see `Config.chrome_options` | Default: none
+    chrome_options : ChromeOptions,
+}
+
+NetworkConditions : NetworkConditions.NetworkConditions
+
+Device : Device.Device
+
+ChromeOptions : ChromeOptions.ChromeOptions
+
+Project : {
+    name : Str,
+    window_size : [Inherit, Override [Size U64 U64]],
+    device : [Inherit, Override Device],
+    # e.g. "de-DE" - the browser default is used when not overridden
+    locale : [Inherit, Override Str],
+    headless : [Inherit, Override [Yes, No]],
 }
 
 ## The default test configuration to run your tests.
@@ -46,6 +78,20 @@ R2EConfiguration test_error : {
 ##
 ## **attempts** - *2*
 ##
+## **browser** - *Chrome*
+##
+## **fail_on_js_errors** - *No*
+##
+## **js_errors_allow_list** - *[]*
+##
+## **network_conditions** - *NoThrottling*
+##
+## **device** - *Desktop*
+##
+## **projects** - *[]*
+##
+## **chrome_options** - *no extra args, prefs, excluded switches or extensions*
+##
 ## ```
 ## app [test_cases, config] { r2e: platform "..." }
 ##
@@ -69,6 +115,13 @@ default_config = {
     window_size: Size(1024, 768),
     screenshot_on_fail: Yes,
     attempts: 2,
+    browser: Chrome,
+    fail_on_js_errors: No,
+    js_errors_allow_list: [],
+    network_conditions: NoThrottling,
+    device: Desktop,
+    projects: [],
+    chrome_options: chrome_options({}),
 }
 
 ## The default test configuration with overrides.
@@ -78,6 +131,7 @@ default_config = {
 ##     results_dir_name: "my-results",
 ##     reporters: [BasicHtmlReporter.reporter, my_json_reporter],
 ##     assert_timeout: 5_000,
+##     browser: Firefox,
 ## })
 ## ```
 default_config_with :
@@ -91,9 +145,16 @@ default_config_with :
         window_size ?? [Size U64 U64],
         screenshot_on_fail ?? [Yes, No],
         attempts ?? U64,
+        browser ?? [Chrome, Firefox],
+        fail_on_js_errors ?? [Yes, No],
+        js_errors_allow_list ?? List Str,
+        network_conditions ?? NetworkConditions,
+        device ?? Device,
+        projects ?? List Project,
+        chrome_options ?? ChromeOptions,
     }
     -> R2EConfiguration _
-default_config_with = |{ results_dir_name ?? default_config.results_dir_name, reporters ?? default_config.reporters, assert_timeout ?? 3_000, page_load_timeout ?? 10_000, script_execution_timeout ?? 10_000, element_implicit_timeout ?? 5_000, window_size ?? Size(1024, 768), screenshot_on_fail ?? Yes, attempts ?? 2 }| {
+default_config_with = |{ results_dir_name ?? default_config.results_dir_name, reporters ?? default_config.reporters, assert_timeout ?? 3_000, page_load_timeout ?? 10_000, script_execution_timeout ?? 10_000, element_implicit_timeout ?? 5_000, window_size ?? Size(1024, 768), screenshot_on_fail ?? Yes, attempts ?? 2, browser ?? Chrome, fail_on_js_errors ?? No, js_errors_allow_list ?? [], network_conditions ?? NoThrottling, device ?? Desktop, projects ?? [], chrome_options ?? default_config.chrome_options }| {
     results_dir_name,
     reporters,
     assert_timeout,
@@ -103,4 +164,84 @@ default_config_with = |{ results_dir_name ?? default_config.results_dir_name, re
     window_size,
     screenshot_on_fail,
     attempts,
+    browser,
+    fail_on_js_errors,
+    js_errors_allow_list,
+    network_conditions,
+    device,
+    projects,
+    chrome_options,
+}
+
+## A named configuration variant - every test runs once per project.
+##
+## The test results and the reports contain the project name,
+## and the `--project somePattern` cli param runs only the matching projects.
+##
+## The overrides from `Test.test_with` take precedence over the project.
+##
+## ```
+## config = Config.default_config_with({
+##     projects: [
+##         Config.project("desktop", {}),
+##         Config.project("phone", { device: Override(IPhone14) }),
+##         Config.project("tablet-de", { device: Override(IPadMini), locale: Override("de-DE") }),
+##         Config.project("wide", { window_size: Override(Size(1920, 1080)), headless: Override(Yes) }),
+##     ],
+## })
+## ```
+project :
+    Str,
+    {
+        window_size ?? [Inherit, Override [Size U64 U64]],
+        device ?? [Inherit, Override Device],
+        locale ?? [Inherit, Override Str],
+        headless ?? [Inherit, Override [Yes, No]],
+    }
+    -> Project
+project = |name, { window_size ?? Inherit, device ?? Inherit, locale ?? Inherit, headless ?? Inherit }| {
+    name,
+    window_size,
+    device,
+    locale,
+    headless,
+}
+
+## Extra options for Chrome - Firefox ignores them.
+##
+## The `--chrome-arg` cli param adds more args, e.g. `--chrome-arg=--disable-gpu`.
+##
+## The unpacked extensions are loaded with `--load-extension` - the paths
+## are relative to the current working directory.
+##
+## The "download.default_directory" pref replaces the download directory of each test -
+## the downloads of all tests are saved to it (see `Browser.wait_for_download!`).
+##
+## ```
+## config = Config.default_config_with({
+##     chrome_options: Config.chrome_options({
+##         args: ["--disable-gpu"],
+##         prefs: [
+##             { name: "intl.accept_languages", value: String("de-DE") },
+##             # 1 - allow, 2 - block
+##             { name: "profile.default_content_setting_values.notifications", value: Number(1) },
+##         ],
+##         exclude_switches: ["enable-automation"],
+##         extensions: ["./my-extension/dist"],
+##     }),
+## })
+## ```
+chrome_options :
+    {
+        args ?? List Str,
+        prefs ?? List { name : Str, value : [String Str, Number F64, Boolean Bool] },
+        exclude_switches ?? List Str,
+        extensions ?? List Str,
+    }
+    -> ChromeOptions
+chrome_options = |{ args ?? [], prefs ?? [], exclude_switches ?? [], extensions ?? [] }| {
+    args,
+    prefs,
+    exclude_switches,
+    extensions,
 }
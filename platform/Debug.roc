@@ -45,7 +45,7 @@ wait_for_enter_key! = |{}|
 ## ```
 ## button |> Debug.show_element!()?
 ## ```
-show_element! : Element => Result {} [WebDriverError Str, JsReturnTypeError Str]
+show_element! : Element => Result {} [WebDriverError Str, Timeout Str, JsReturnTypeError Str]
 show_element! = |element|
     { session_id, locator } = Internal.unpack_element_data(element)
     DebugMode.flash_elements!(session_id, locator, Single)?
@@ -61,7 +61,7 @@ show_element! = |element|
 ## ```
 ## checkboxes |> Debug.show_elements!()?
 ## ```
-show_elements! : List Element => Result {} [WebDriverError Str, JsReturnTypeError Str]
+show_elements! : List Element => Result {} [WebDriverError Str, Timeout Str, JsReturnTypeError Str]
 show_elements! = |elements|
     when elements is
         [] -> Ok({})
@@ -80,7 +80,7 @@ show_elements! = |elements|
 ## ```
 ## browser |> Debug.show_current_frame!()?
 ## ```
-show_current_frame! : Browser => Result {} [WebDriverError Str, JsReturnTypeError Str]
+show_current_frame! : Browser => Result {} [WebDriverError Str, Timeout Str, JsReturnTypeError Str]
 show_current_frame! = |browser|
     { session_id } = Internal.unpack_browser_data(browser)
     DebugMode.flash_current_frame!(session_id)?
@@ -2,7 +2,7 @@ module [run_if_debug_mode!, flash_elements!, flash_current_frame!, wait!, show_d
 
 import Effect
 import Common.ExecuteJs as ExecuteJs
-import Common.Locator exposing [Locator]
+import Common.Locator exposing [ElementLocator]
 import Internal
 
 is_debug_mode! : {} => Bool
@@ -42,7 +42,7 @@ run_if_debug_mode! = |task!|
     else
         {}
 
-flash_elements! : Str, Locator, [All, Single] => Result {} [JsReturnTypeError Str, WebDriverError Str]
+flash_elements! : Str, ElementLocator, [All, Single] => Result {} [JsReturnTypeError Str, WebDriverError Str, Timeout Str]
 flash_elements! = |session_id, locator, quantity|
     # TODO better tests
     blink_script =
@@ -85,7 +85,7 @@ flash_elements! = |session_id, locator, quantity|
 
     Ok({})
 
-flash_current_frame! : Str => Result {} [JsReturnTypeError Str, WebDriverError Str]
+flash_current_frame! : Str => Result {} [JsReturnTypeError Str, WebDriverError Str, Timeout Str]
 flash_current_frame! = |session_id|
     # TODO better tests
     blink_script =
@@ -130,7 +130,7 @@ flash_current_frame! = |session_id|
 
     Ok({})
 
-locator_to_script_execution : Locator, [Single, All] -> Str
+locator_to_script_execution : ElementLocator, [Single, All] -> Str
 locator_to_script_execution = |locator, quantity|
     when quantity is
         Single ->
@@ -171,6 +171,9 @@ locator_to_script_execution = |locator, quantity|
                     window.r2eFlash(el);
                     """
 
+                # the elements returned from js cannot be found again
+                JsResult -> ""
+
         All ->
             when locator is
                 Css(str) ->
@@ -218,7 +221,10 @@ locator_to_script_execution = |locator, quantity|
                     }
                     """
 
-show_debug_message_in_browser! : Str, Str => Result {} [WebDriverError Str, JsReturnTypeError Str]
+                # the elements returned from js cannot be found again
+                JsResult -> ""
+
+show_debug_message_in_browser! : Str, Str => Result {} [WebDriverError Str, Timeout Str, JsReturnTypeError Str]
 show_debug_message_in_browser! = |session_id, message|
     browser = Internal.pack_browser_data({ session_id })
 
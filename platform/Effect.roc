@@ -7,6 +7,16 @@ hosted [
     reset_test_overrides!,
     set_window_size!,
     set_window_size_override!,
+    set_network_conditions!,
+    set_network_conditions_override!,
+    set_device!,
+    set_device_override!,
+    set_chrome_options!,
+    set_locale_override!,
+    set_headless_override!,
+    browser_set_network_conditions!,
+    set_results_dir!,
+    set_browser!,
     get_assert_timeout!,
     stdout_line!,
     stdin_line!,
@@ -20,12 +30,28 @@ hosted [
     get_time_milis!,
     is_debug_mode!,
     is_verbose!,
+    is_setup_run!,
+    run_setup!,
     reset_test_log_bucket!,
     get_logs_from_bucket!,
     get_test_name_filter!,
+    get_project_filter!,
     create_dir_if_not_exist!,
     file_write_utf8!,
     browser_get_screenshot!,
+    browser_get_console_logs!,
+    browser_get_js_errors!,
+    network_add_route!,
+    network_clear_routes!,
+    network_get_requests!,
+    network_take_response!,
+    browser_wait_for_network_idle!,
+    browser_wait_for_dom_stable!,
+    browser_wait_for_download!,
+    browser_get_downloads!,
+    browser_read_download!,
+    browser_get_timeouts!,
+    browser_set_timeouts!,
     add_cookie!,
     get_cookie!,
     get_all_cookies!,
@@ -35,21 +61,36 @@ hosted [
     alert_send_text!,
     alert_get_text!,
     alert_accept!,
-    # browserGetPdf,
+    browser_print_pdf!,
     element_get_text!,
     element_is_selected!,
     element_is_displayed!,
+    element_is_enabled!,
+    element_get_computed_role!,
+    element_get_computed_label!,
     element_get_attribute!,
     element_get_property!,
     element_send_keys!,
     element_clear!,
+    perform_actions!,
+    release_actions!,
     element_find_element!,
     element_find_elements!,
+    element_get_shadow_root!,
+    shadow_root_find_element!,
+    shadow_root_find_elements!,
     element_get_css!,
     element_get_tag!,
     element_get_rect!,
+    element_get_screenshot!,
     browser_set_window_rect!,
     browser_get_window_rect!,
+    browser_get_active_element!,
+    browser_get_window_handle!,
+    browser_get_window_handles!,
+    browser_new_window!,
+    browser_switch_to_window!,
+    browser_close_current_window!,
     browser_get_title!,
     browser_get_url!,
     browser_reload!,
@@ -59,10 +100,17 @@ hosted [
     browser_minimize!,
     browser_full_screen!,
     execute_js!,
+    execute_js_async!,
+    execute_js_json!,
+    execute_js_elements!,
     get_env!,
     get_page_source!,
     switch_to_frame_by_element_id!,
+    switch_to_frame_by_index!,
     switch_to_parent_frame!,
+    switch_to_top_level_frame!,
+    get_frame_depth!,
+    restore_frame_depth!,
 ]
 
 # effects that are provided by the host
@@ -82,6 +130,26 @@ set_window_size! : Str => {}
 
 set_window_size_override! : Str => {}
 
+set_network_conditions! : Str => {}
+
+set_network_conditions_override! : Str => {}
+
+set_device! : Str => {}
+
+set_device_override! : Str => {}
+
+set_chrome_options! : Str => {}
+
+set_locale_override! : Str => {}
+
+set_headless_override! : I64 => {}
+
+browser_set_network_conditions! : Str, Str => Result {} Str
+
+set_results_dir! : Str => {}
+
+set_browser! : Str => {}
+
 get_assert_timeout! : {} => U64
 
 stdout_line! : Str => {}
@@ -96,12 +164,18 @@ is_debug_mode! : {} => I64
 
 is_verbose! : {} => I64
 
+is_setup_run! : {} => I64
+
+run_setup! : {} => I64
+
 reset_test_log_bucket! : {} => {}
 
 get_logs_from_bucket! : {} => List Str
 
 get_test_name_filter! : {} => Str
 
+get_project_filter! : {} => Str
+
 # file system
 create_dir_if_not_exist! : Str => Result {} Str
 
@@ -127,9 +201,49 @@ browser_set_window_rect! : Str, I64, I64, I64, I64, I64 => Result (List I64) Str
 
 browser_get_window_rect! : Str => Result (List I64) Str
 
+browser_get_active_element! : Str => Result Str Str
+
+browser_get_console_logs! : Str => Result Str Str
+
+browser_get_js_errors! : Str, Str => Result (List Str) Str
+
+network_add_route! : Str, Str => Result {} Str
+
+network_clear_routes! : Str => Result {} Str
+
+network_get_requests! : Str => Result Str Str
+
+network_take_response! : Str, Str => Result Str Str
+
+browser_wait_for_network_idle! : Str, I64 => Result {} Str
+
+browser_wait_for_dom_stable! : Str, I64 => Result {} Str
+
+browser_wait_for_download! : Str, Str, I64 => Result Str Str
+
+browser_get_downloads! : Str => Result (List Str) Str
+
+browser_read_download! : Str, Str => Result (List U8) Str
+
+browser_get_timeouts! : Str => Result (List I64) Str
+
+browser_set_timeouts! : Str, I64, I64, I64 => Result {} Str
+
+browser_get_window_handle! : Str => Result Str Str
+
+browser_get_window_handles! : Str => Result (List Str) Str
+
+browser_new_window! : Str, Str => Result Str Str
+
+browser_switch_to_window! : Str, Str => Result {} Str
+
+browser_close_current_window! : Str => Result (List Str) Str
+
 browser_get_screenshot! : Str => Result Str Str
 
-# browserGetPdf : Str, F64, F64, F64, F64, F64, F64, F64, Str, I64, I64, List Str -> Task Str Str
+element_get_screenshot! : Str, Str => Result Str Str
+
+browser_print_pdf! : Str, Str, F64, F64, F64, F64, F64, F64, F64, Str, I64, I64, Str => Result Str Str
 
 browser_navigate_back! : Str => Result {} Str
 
@@ -145,6 +259,12 @@ browser_full_screen! : Str => Result (List I64) Str
 
 execute_js! : Str, Str, Str => Result Str Str
 
+execute_js_async! : Str, Str, Str => Result Str Str
+
+execute_js_json! : Str, Str, Str => Result Str Str
+
+execute_js_elements! : Str, Str, Str => Result (List Str) Str
+
 add_cookie! : Str, Str, Str, Str, Str, Str, I64, I64, I64 => Result {} Str
 
 delete_cookie! : Str, Str => Result {} Str
@@ -170,12 +290,22 @@ element_send_keys! : Str, Str, Str => Result {} Str
 
 element_clear! : Str, Str => Result {} Str
 
+perform_actions! : Str, Str => Result {} Str
+
+release_actions! : Str => Result {} Str
+
 element_get_text! : Str, Str => Result Str Str
 
 element_is_selected! : Str, Str => Result Str Str
 
 element_is_displayed! : Str, Str => Result Str Str
 
+element_is_enabled! : Str, Str => Result Str Str
+
+element_get_computed_role! : Str, Str => Result Str Str
+
+element_get_computed_label! : Str, Str => Result Str Str
+
 element_get_attribute! : Str, Str, Str => Result Str Str
 
 element_get_property! : Str, Str, Str => Result Str Str
@@ -184,6 +314,12 @@ element_find_element! : Str, Str, Str, Str => Result Str Str
 
 element_find_elements! : Str, Str, Str, Str => Result (List Str) Str
 
+element_get_shadow_root! : Str, Str => Result Str Str
+
+shadow_root_find_element! : Str, Str, Str, Str => Result Str Str
+
+shadow_root_find_elements! : Str, Str, Str, Str => Result (List Str) Str
+
 element_get_tag! : Str, Str => Result Str Str
 
 element_get_css! : Str, Str, Str => Result Str Str
@@ -196,4 +332,12 @@ get_page_source! : Str => Result Str Str
 
 switch_to_frame_by_element_id! : Str, Str => Result {} Str
 
+switch_to_frame_by_index! : Str, I64 => Result {} Str
+
 switch_to_parent_frame! : Str => Result {} Str
+
+switch_to_top_level_frame! : Str => Result {} Str
+
+get_frame_depth! : Str => U64
+
+restore_frame_depth! : Str, U64 => Result {} Str
@@ -6,8 +6,15 @@ module [
     get_value!,
     input_text!,
     clear!,
+    hover!,
+    double_click!,
+    right_click!,
+    drag_and_drop_to!,
     is_selected!,
     is_visible!,
+    is_enabled!,
+    get_accessible_role!,
+    get_accessible_name!,
     get_property!,
     get_attribute!,
     get_attribute_or_empty!,
@@ -15,20 +22,24 @@ module [
     get_tag_name!,
     get_css_property!,
     get_rect!,
+    take_screenshot_base64!,
     Locator,
     find_element!,
     find_elements!,
     find_single_element!,
     try_find_element!,
     use_iframe!,
+    get_shadow_root!,
 ]
 
-import Internal exposing [Element]
+import Internal exposing [Element, ShadowRoot]
 import InternalElement
 import InternalError
+import InternalFrame
 import PropertyDecoder
 import Common.Locator as Locator
 import Effect
+import Actions
 import Debug
 import DebugMode
 
@@ -40,7 +51,7 @@ import DebugMode
 ## # click the button
 ## button |> Element.click!()?
 ## ```
-click! : Element => Result {} [WebDriverError Str, ElementNotFound Str]
+click! : Element => Result {} [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, ElementNotInteractable Str, ElementClickIntercepted Str]
 click! = |element|
     { session_id, element_id, locator, selector_text } = Internal.unpack_element_data(element)
 
@@ -49,7 +60,7 @@ click! = |element|
             Debug.print_line!("Trying to click element: ${selector_text}"),
     )
 
-    Effect.element_click!(session_id, element_id) |> Result.map_err(InternalError.handle_element_error)?
+    Effect.element_click!(session_id, element_id) |> Result.map_err(InternalError.handle_interaction_error)?
 
     DebugMode.run_if_verbose!(
         |{}|
@@ -78,7 +89,7 @@ click! = |element|
 ## # get button text
 ## button_text = button |> Element.get_text!()?
 ## ```
-get_text! : Element => Result Str [WebDriverError Str, ElementNotFound Str]
+get_text! : Element => Result Str [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
 get_text! = |element|
     { selector_text } = Internal.unpack_element_data(element)
 
@@ -111,7 +122,7 @@ get_text! = |element|
 ## input_value = input |> Element.get_value!()?
 ## input_value |> Assert.should_be(18)
 ## ```
-get_value! : Element => Result a [ElementNotFound Str, PropertyTypeError Str, WebDriverError Str] where a implements Decoding
+get_value! : Element => Result a [ElementNotFound Str, StaleElementReference Str, PropertyTypeError Str, WebDriverError Str] where a implements Decoding
 get_value! = |element|
     get_property!(element, "value")
 
@@ -127,7 +138,7 @@ get_value! = |element|
 ## # asert expected value
 ## is_tasty_state |> Assert.should_be(Selected)
 ## ```
-is_selected! : Element => Result [Selected, NotSelected] [WebDriverError Str, ElementNotFound Str]
+is_selected! : Element => Result [Selected, NotSelected] [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
 is_selected! = |element|
     { session_id, element_id, selector_text } = Internal.unpack_element_data(element)
 
@@ -153,7 +164,7 @@ is_selected! = |element|
 ## # assert expected value
 ## is_visible |> Assert.should_be(Visible)
 ## ```
-is_visible! : Element => Result [Visible, NotVisible] [WebDriverError Str, ElementNotFound Str]
+is_visible! : Element => Result [Visible, NotVisible] [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
 is_visible! = |element|
     { selector_text } = Internal.unpack_element_data(element)
 
@@ -164,6 +175,78 @@ is_visible! = |element|
 
     InternalElement.is_visible!(element)
 
+## Check if `Element` is enabled.
+##
+## Only form controls (buttons, inputs, selects, textareas, options...)
+## can be disabled - every other `Element` is reported as `Enabled`.
+##
+## ```
+## # find submit button
+## button = browser |> Browser.find_element!(Css("#submit-button"))?
+## # check if the button can be used
+## is_enabled = button |> Element.is_enabled!()?
+## # assert expected value
+## is_enabled |> Assert.should_be(Enabled)
+## ```
+is_enabled! : Element => Result [Enabled, Disabled] [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
+is_enabled! = |element|
+    { selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Checking if element is enabled: ${selector_text}"),
+    )
+
+    InternalElement.is_enabled!(element)
+
+## Get the ARIA role of an `Element`, as computed by the browser.
+##
+## The role comes from the `role` attribute or is implied by the tag,
+## e.g. `<button>` has the role `"button"` and `<nav>` has `"navigation"`.
+##
+## ```
+## # find menu element
+## menu = browser |> Browser.find_element!(Css("#main-menu"))?
+## # get the role
+## role = menu |> Element.get_accessible_role!()?
+## # assert expected value
+## role |> Assert.should_be("navigation")
+## ```
+get_accessible_role! : Element => Result Str [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
+get_accessible_role! = |element|
+    { selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Getting accessible role of element: ${selector_text}"),
+    )
+
+    InternalElement.get_accessible_role!(element)
+
+## Get the accessible name of an `Element`, as computed by the browser.
+##
+## This is the name that screen readers announce - it comes from
+## `aria-label`, `aria-labelledby`, a `<label>` or the text content.
+##
+## ```
+## # find icon button
+## close_button = browser |> Browser.find_element!(Css("#close-dialog"))?
+## # get the accessible name
+## name = close_button |> Element.get_accessible_name!()?
+## # assert expected value
+## name |> Assert.should_be("Close")
+## ```
+get_accessible_name! : Element => Result Str [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
+get_accessible_name! = |element|
+    { selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Getting accessible name of element: ${selector_text}"),
+    )
+
+    InternalElement.get_accessible_name!(element)
+
 ## Get **attribute** of an `Element`.
 ##
 ## **Attributes** are values you can see in the HTML DOM, like *<input class"test" type="password" />*
@@ -176,7 +259,7 @@ is_visible! = |element|
 ## # get input type
 ## input_type = input |> Element.get_attribute!("type")?
 ## ```
-get_attribute! : Element, Str => Result Str [WebDriverError Str, ElementNotFound Str]
+get_attribute! : Element, Str => Result Str [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
 get_attribute! = |element, attribute_name|
     { session_id, element_id, selector_text } = Internal.unpack_element_data(element)
 
@@ -197,7 +280,7 @@ get_attribute! = |element, attribute_name|
 ##     Ok(type) -> type |> Assert.should_be("checkbox")
 ##     Err(Empty) -> Assert.fail_with("should not be empty")
 ## ```
-get_attribute_or_empty! : Element, Str => Result (Result Str [Empty]) [WebDriverError Str, ElementNotFound Str]
+get_attribute_or_empty! : Element, Str => Result (Result Str [Empty]) [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
 get_attribute_or_empty! = |element, attribute_name|
     { session_id, element_id, selector_text } = Internal.unpack_element_data(element)
 
@@ -249,7 +332,7 @@ get_attribute_or_empty! = |element, attribute_name|
 ## client_height = name_input |> Element.get_property!("clientHeight")?
 ## client_height |> Assert.should_be(17)
 ## ```
-get_property! : Internal.Element, Str => Result a [ElementNotFound Str, PropertyTypeError Str, WebDriverError Str] where a implements Decoding
+get_property! : Internal.Element, Str => Result a [ElementNotFound Str, StaleElementReference Str, PropertyTypeError Str, WebDriverError Str] where a implements Decoding
 get_property! = |element, property_name|
     { selector_text } = Internal.unpack_element_data(element)
 
@@ -287,7 +370,7 @@ get_property! = |element, property_name|
 ## client_height = name_input |> Element.get_property!("clientHeight")?
 ## client_height |> Assert.should_be(Ok(17))
 ## ```
-get_property_or_empty! : Element, Str => Result (Result a [Empty]) [WebDriverError Str, ElementNotFound Str, PropertyTypeError Str] where a implements Decoding
+get_property_or_empty! : Element, Str => Result (Result a [Empty]) [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, PropertyTypeError Str] where a implements Decoding
 get_property_or_empty! = |element, property_name|
     { session_id, element_id, selector_text } = Internal.unpack_element_data(element)
 
@@ -321,15 +404,28 @@ get_property_or_empty! = |element, property_name|
 ##
 ## Special key sequences:
 ##
-## `{enter}` - simulates an "enter" key press
+## `{enter}`, `{tab}`, `{backspace}`, `{delete}`, `{esc}`, `{space}`, `{insert}`
+##
+## `{up}`, `{down}`, `{left}`, `{right}`, `{home}`, `{end}`, `{pageup}`, `{pagedown}`
+##
+## `{f1}` - `{f12}`
+##
+## `{ctrl}`, `{shift}`, `{alt}`, `{meta}` - the modifier stays pressed until the end of the text
+##
+## Modifiers can be combined with other keys - e.g. `{ctrl+a}`, `{shift+tab}`, `{ctrl+shift+left}` -
+## the modifiers are released right after the key.
+##
+## Use `{{` and `}}` to type literal braces.
 ##
 ## ```
 ## # find search input element
 ## search_input = browser |> Browser.find_element!(Css("#search"))?
 ## # input text and submit
 ## search_input |> Element.send_keys!("roc lang{enter}")?
+## # replace the text
+## search_input |> Element.send_keys!("{ctrl+a}{backspace}roc platform{enter}")?
 ## ```
-input_text! : Element, Str => Result {} [WebDriverError Str, ElementNotFound Str]
+input_text! : Element, Str => Result {} [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, ElementNotInteractable Str, ElementClickIntercepted Str]
 input_text! = |element, str|
     { session_id, element_id, selector_text, locator } = Internal.unpack_element_data(element)
 
@@ -338,7 +434,7 @@ input_text! = |element, str|
             Debug.print_line!("Sending text \"${str}\" to element: ${selector_text}"),
     )
 
-    Effect.element_send_keys!(session_id, element_id, str) |> Result.map_err(InternalError.handle_element_error)?
+    Effect.element_send_keys!(session_id, element_id, str) |> Result.map_err(InternalError.handle_interaction_error)?
 
     DebugMode.run_if_verbose!(
         |{}|
@@ -363,7 +459,7 @@ input_text! = |element, str|
 ## # click the button
 ## input |> Element.clear!()?
 ## ```
-clear! : Internal.Element => Result {} [WebDriverError Str, ElementNotFound Str]
+clear! : Internal.Element => Result {} [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, ElementNotInteractable Str, ElementClickIntercepted Str]
 clear! = |element|
     { session_id, element_id, selector_text, locator } = Internal.unpack_element_data(element)
 
@@ -372,7 +468,7 @@ clear! = |element|
             Debug.print_line!("Clearing element: ${selector_text}"),
     )
 
-    Effect.element_clear!(session_id, element_id) |> Result.map_err(InternalError.handle_element_error)?
+    Effect.element_clear!(session_id, element_id) |> Result.map_err(InternalError.handle_interaction_error)?
 
     DebugMode.run_if_verbose!(
         |{}|
@@ -389,6 +485,132 @@ clear! = |element|
 
     Ok({})
 
+## Take a screenshot of the `Element`.
+##
+## The `Element` is scrolled into view before the screenshot is taken.
+##
+## The result will be a **base64** encoded `Str` representation of a PNG file.
+##
+## ```
+## # find the widget under test
+## widget = browser |> Browser.find_element!(Css("#date-picker"))?
+## # take a screenshot of the widget only
+## base64_png_str = widget |> Element.take_screenshot_base64!()?
+## ```
+take_screenshot_base64! : Element => Result Str [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
+take_screenshot_base64! = |element|
+    { session_id, element_id, selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Taking screenshot of element: ${selector_text}"),
+    )
+
+    Effect.element_get_screenshot!(session_id, element_id) |> Result.map_err(InternalError.handle_element_error)
+
+## Move the mouse over the `Element`.
+##
+## ```
+## # find menu element
+## menu = browser |> Browser.find_element!(Css("#menu"))?
+## # hover over the menu
+## menu |> Element.hover!()?
+## ```
+hover! : Element => Result {} [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, ElementNotInteractable Str, ElementClickIntercepted Str]
+hover! = |element|
+    { selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Hovering over element: ${selector_text}"),
+    )
+
+    Actions.new(Mouse)
+    |> Actions.move_to_element(element, { x: 0, y: 0 })
+    |> perform_on_element!(element)
+
+## Double click on the `Element`.
+##
+## ```
+## # find item element
+## item = browser |> Browser.find_element!(Css(".list-item"))?
+## # double click the item
+## item |> Element.double_click!()?
+## ```
+double_click! : Element => Result {} [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, ElementNotInteractable Str, ElementClickIntercepted Str]
+double_click! = |element|
+    { selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Trying to double click element: ${selector_text}"),
+    )
+
+    Actions.new(Mouse)
+    |> Actions.move_to_element(element, { x: 0, y: 0 })
+    |> Actions.double_click(Left)
+    |> perform_on_element!(element)
+
+## Right click on the `Element`.
+##
+## ```
+## # find item element
+## item = browser |> Browser.find_element!(Css(".list-item"))?
+## # open the context menu
+## item |> Element.right_click!()?
+## ```
+right_click! : Element => Result {} [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, ElementNotInteractable Str, ElementClickIntercepted Str]
+right_click! = |element|
+    { selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Trying to right click element: ${selector_text}"),
+    )
+
+    Actions.new(Mouse)
+    |> Actions.move_to_element(element, { x: 0, y: 0 })
+    |> Actions.click(Right)
+    |> perform_on_element!(element)
+
+## Drag the `Element` and drop it on the target `Element`.
+##
+## This uses mouse events - the native HTML5 drag and drop is not supported by the WebDriver.
+##
+## ```
+## card = browser |> Browser.find_element!(Css("#card"))?
+## column = browser |> Browser.find_element!(Css("#done-column"))?
+## card |> Element.drag_and_drop_to!(column)?
+## ```
+drag_and_drop_to! : Element, Element => Result {} [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, ElementNotInteractable Str, ElementClickIntercepted Str]
+drag_and_drop_to! = |element, target|
+    { selector_text } = Internal.unpack_element_data(element)
+    { selector_text: target_selector_text } = Internal.unpack_element_data(target)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Dragging element: ${selector_text} to: ${target_selector_text}"),
+    )
+
+    Actions.new(Mouse)
+    |> Actions.move_to_element(element, { x: 0, y: 0 })
+    |> Actions.pointer_down(Left)
+    |> Actions.pause(100)
+    |> Actions.move_to_element(target, { x: 0, y: 0 })
+    |> Actions.pointer_up(Left)
+    |> perform_on_element!(element)
+
+perform_on_element! : Actions.Actions, Element => Result {} [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, ElementNotInteractable Str, ElementClickIntercepted Str]
+perform_on_element! = |actions, element|
+    { session_id, locator } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_debug_mode!(
+        |{}|
+            DebugMode.flash_elements!(session_id, locator, Single),
+    )
+
+    Internal.pack_browser_data({ session_id }) |> Actions.perform!(actions)
+
 ## Supported locator strategies
 ##
 ## `Css Str` - e.g. Css(".my-button-class")
@@ -424,7 +646,7 @@ Locator : Locator.Locator
 ## # find the html element with an attribute [data-testid="my-element"]
 ## button = element |> Element.find_element!(TestId("my-element"))?
 ## ```
-find_element! : Element, Locator => Result Element [WebDriverError Str, ElementNotFound Str]
+find_element! : Element, Locator => Result Element [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, InvalidSelector Str]
 find_element! = |element, locator|
     { session_id, element_id } = Internal.unpack_element_data(element)
     (using, value) = Locator.get_locator(locator)
@@ -436,7 +658,7 @@ find_element! = |element, locator|
             Debug.print_line!("Searching for element: ${selector_text}"),
     )
 
-    new_element_id = Effect.element_find_element!(session_id, element_id, using, value) |> Result.map_err(InternalError.handle_element_error)?
+    new_element_id = Effect.element_find_element!(session_id, element_id, using, value) |> Result.map_err(InternalError.handle_find_error)?
 
     DebugMode.run_if_verbose!(
         |{}|
@@ -446,12 +668,12 @@ find_element! = |element, locator|
     DebugMode.run_if_debug_mode!(
         |{}|
             DebugMode.show_debug_message_in_browser!(session_id, "Find Element ${selector_text}")?
-            DebugMode.flash_elements!(session_id, locator, Single)?
+            DebugMode.flash_elements!(session_id, Locator.to_element_locator(locator), Single)?
             DebugMode.wait!({})
             Ok({}),
     )
 
-    Internal.pack_element_data({ session_id, element_id: new_element_id, selector_text, locator }) |> Ok
+    Internal.pack_element_data({ session_id, element_id: new_element_id, selector_text, locator: Locator.to_element_locator(locator) }) |> Ok
 
 ## Find an `Element` inside the tree of another `Element` in the `Browser`.
 ##
@@ -472,7 +694,7 @@ find_element! = |element, locator|
 ##         button_text = el |> Element.get_text!()?
 ##         Stdout.line!("Button found with text: $(button_text)")
 ## ```
-try_find_element! : Element, Locator => Result [Found Element, NotFound] [WebDriverError Str, ElementNotFound Str]
+try_find_element! : Element, Locator => Result [Found Element, NotFound] [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, InvalidSelector Str]
 try_find_element! = |element, locator|
     find_element!(element, locator)
     |> Result.map_ok(Found)
@@ -495,7 +717,7 @@ try_find_element! = |element, locator|
 ## ```
 ## button = element |> Element.find_single_element!(Css("#submit-button"))?
 ## ```
-find_single_element! : Element, Locator => Result Element [AssertionError Str, ElementNotFound Str, WebDriverError Str]
+find_single_element! : Element, Locator => Result Element [AssertionError Str, ElementNotFound Str, StaleElementReference Str, InvalidSelector Str, WebDriverError Str]
 find_single_element! = |element, locator|
     { selector_text: parent_element_selector_text } = Internal.unpack_element_data(element)
     elements = find_elements!(element, locator)?
@@ -524,7 +746,7 @@ find_single_element! = |element, locator|
 ## list_items = element |> Element.find_elements!(Css("#my-list li"))?
 ## ```
 ##
-find_elements! : Element, Locator => Result (List Element) [WebDriverError Str, ElementNotFound Str]
+find_elements! : Element, Locator => Result (List Element) [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, InvalidSelector Str]
 find_elements! = |element, locator|
     { session_id, element_id: parent_element_id } = Internal.unpack_element_data(element)
     (using, value) = Locator.get_locator(locator)
@@ -536,7 +758,7 @@ find_elements! = |element, locator|
             Debug.print_line!("Searching for elements: ${selector_text}"),
     )
 
-    result = Effect.element_find_elements!(session_id, parent_element_id, using, value) |> Result.map_err(InternalError.handle_element_error)
+    result = Effect.element_find_elements!(session_id, parent_element_id, using, value) |> Result.map_err(InternalError.handle_find_error)
 
     when result is
         Ok(element_ids) ->
@@ -551,7 +773,7 @@ find_elements! = |element, locator|
                         Ok({})
                     else
                         DebugMode.show_debug_message_in_browser!(session_id, "Find Elements ${selector_text}")?
-                        DebugMode.flash_elements!(session_id, locator, All)?
+                        DebugMode.flash_elements!(session_id, Locator.to_element_locator(locator), All)?
                         DebugMode.wait!({})
                         Ok({}),
             )
@@ -559,13 +781,38 @@ find_elements! = |element, locator|
             element_ids
             |> List.map(
                 |element_id|
-                    Internal.pack_element_data({ session_id, element_id, selector_text, locator }),
+                    Internal.pack_element_data({ session_id, element_id, selector_text, locator: Locator.to_element_locator(locator) }),
             )
             |> Ok
 
         Err(ElementNotFound(_)) -> Ok([])
         Err(err) -> Err(err)
 
+## Get the shadow root of an `Element` - e.g. a web component.
+##
+## Use the `ShadowRoot` module to find `Elements` inside of the shadow root.
+##
+## ```
+## # find the web component
+## date_picker = browser |> Browser.find_element!(Css("my-date-picker"))?
+## # get the shadow root of the component
+## shadow_root = date_picker |> Element.get_shadow_root!()?
+## # find an element inside the shadow DOM
+## input = shadow_root |> ShadowRoot.find_element!(Css("input"))?
+## ```
+get_shadow_root! : Element => Result ShadowRoot [WebDriverError Str, ElementNotFound Str, StaleElementReference Str, ShadowRootNotFound Str]
+get_shadow_root! = |element|
+    { session_id, element_id, selector_text } = Internal.unpack_element_data(element)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Getting shadow root of element: ${selector_text}"),
+    )
+
+    shadow_root_id = Effect.element_get_shadow_root!(session_id, element_id) |> Result.map_err(InternalError.handle_shadow_root_error)?
+
+    Internal.pack_shadow_root_data({ session_id, shadow_root_id, selector_text }) |> Ok
+
 ## Get the HTML tag name of an `Element`.
 ##
 ## ```
@@ -576,7 +823,7 @@ find_elements! = |element, locator|
 ## # tag name should be "input"
 ## tag_name |> Assert.should_be("input")
 ## ```
-get_tag_name! : Element => Result Str [WebDriverError Str, ElementNotFound Str]
+get_tag_name! : Element => Result Str [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
 get_tag_name! = |element|
     { session_id, element_id, selector_text } = Internal.unpack_element_data(element)
 
@@ -597,7 +844,7 @@ get_tag_name! = |element|
 ## # assert
 ## input_border |> Assert.should_be("2px solid rgb(0, 0, 0)")
 ## ```
-get_css_property! : Element, Str => Result Str [WebDriverError Str, ElementNotFound Str]
+get_css_property! : Element, Str => Result Str [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
 get_css_property! = |element, css_property|
     { session_id, element_id, selector_text } = Internal.unpack_element_data(element)
 
@@ -628,7 +875,7 @@ ElementRect : {
 ## rect.x |> Assert.should_be_equal_to(226.1243566)?
 ## rect.y |> Assert.should_be_equal_to(218.3593754)
 ## ```
-get_rect! : Element => Result ElementRect [WebDriverError Str, ElementNotFound Str]
+get_rect! : Element => Result ElementRect [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
 get_rect! = |element|
     { session_id, element_id, selector_text } = Internal.unpack_element_data(element)
 
@@ -651,6 +898,9 @@ get_rect! = |element|
 ## This function runs a callback in which you can interact
 ## with the page inside an iFrame.
 ##
+## After the callback the previous frame context is restored,
+## even when the callback failed inside nested frames.
+##
 ## ```
 ## frame_el = browser |> Browser.find_element!(Css("iframe"))?
 ##
@@ -663,37 +913,9 @@ use_iframe! : Element, (Internal.Browser => Result {} _) => Result {} _
 use_iframe! = |element, callback!|
     { session_id, element_id, selector_text } = Internal.unpack_element_data(element)
 
-    DebugMode.run_if_verbose!(
-        |{}|
-            Debug.print_line!("Switching context to iFrame: ${selector_text}"),
-    )
-
-    Effect.switch_to_frame_by_element_id!(session_id, element_id) |> Result.map_err(WebDriverError)?
-
-    DebugMode.run_if_debug_mode!(
-        |{}|
-            DebugMode.show_debug_message_in_browser!(session_id, "Switched to iFrame ${selector_text}")?
-            DebugMode.flash_current_frame!(session_id)?
-            DebugMode.wait!({})
-            Ok({}),
-    )
-
-    browser = Internal.pack_browser_data({ session_id })
-    result = callback!(browser)
-
-    DebugMode.run_if_verbose!(
-        |{}|
-            Debug.print_line!("Switching back to iFrame parent"),
+    InternalFrame.use_frame!(
+        session_id,
+        "iFrame ${selector_text}",
+        |{}| Effect.switch_to_frame_by_element_id!(session_id, element_id) |> Result.map_err(InternalError.handle_frame_error),
+        callback!,
     )
-
-    Effect.switch_to_parent_frame!(session_id) |> Result.map_err(WebDriverError)?
-
-    DebugMode.run_if_debug_mode!(
-        |{}|
-            DebugMode.show_debug_message_in_browser!(session_id, "Switched back to iFrame parent")?
-            DebugMode.flash_current_frame!(session_id)?
-            DebugMode.wait!({})
-            Ok({}),
-    )
-
-    result
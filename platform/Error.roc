@@ -6,6 +6,20 @@ web_driver_error_to_str = |error_tag|
     when error_tag is
         WebDriverError(msg) -> StringError("WebDriverError: ${msg}")
         ElementNotFound(msg) -> StringError("ElementNotFound: ${msg}")
+        StaleElementReference(msg) -> StringError("StaleElementReference: ${msg}")
+        ElementClickIntercepted(msg) -> StringError("ElementClickIntercepted: ${msg}")
+        ElementNotInteractable(msg) -> StringError("ElementNotInteractable: ${msg}")
+        InvalidSelector(msg) -> StringError("InvalidSelector: ${msg}")
+        Timeout(msg) -> StringError("Timeout: ${msg}")
+        NoSuchWindow(msg) -> StringError("NoSuchWindow: ${msg}")
+        NoSuchFrame(msg) -> StringError("NoSuchFrame: ${msg}")
+        UnexpectedAlertOpen(msg) -> StringError("UnexpectedAlertOpen: ${msg}")
+        SessionNotCreated(msg) -> StringError("SessionNotCreated: ${msg}")
+        AlertNotFound(msg) -> StringError("AlertNotFound: ${msg}")
+        CookieNotFound(msg) -> StringError("CookieNotFound: ${msg}")
+        ShadowRootNotFound(msg) -> StringError("ShadowRootNotFound: ${msg}")
+        DetachedShadowRoot(msg) -> StringError("DetachedShadowRoot: ${msg}")
         AssertionError(msg) -> StringError("AssertionError: ${msg}")
         PropertyTypeError(msg) -> StringError("PropertyTypeError: ${msg}")
+        JsError(msg) -> StringError("JsError: ${msg}")
         err -> err
@@ -3,11 +3,14 @@ module [
     unpack_browser_data,
     pack_element_data,
     unpack_element_data,
+    pack_shadow_root_data,
+    unpack_shadow_root_data,
     Browser,
     Element,
+    ShadowRoot,
 ]
 
-import Common.Locator exposing [Locator]
+import Common.Locator exposing [ElementLocator]
 
 # ----------------------------------------------------------------
 
@@ -20,7 +23,14 @@ Element := {
     element_id : Str,
     # used to provide better context in Asserts
     selector_text : Str,
-    locator : Locator,
+    locator : ElementLocator,
+}
+
+ShadowRoot := {
+    session_id : Str,
+    shadow_root_id : Str,
+    # selector of the host element
+    selector_text : Str,
 }
 
 pack_browser_data = |data|
@@ -34,3 +44,9 @@ pack_element_data = |data|
 
 unpack_element_data = |@Element(data)|
     data
+
+pack_shadow_root_data = |data|
+    @ShadowRoot(data)
+
+unpack_shadow_root_data = |@ShadowRoot(data)|
+    data
@@ -0,0 +1,54 @@
+module [ConsoleLog, get_console_logs!, get_js_errors!]
+
+import Effect
+import JsonDecoder
+import EncodeDecode
+
+ConsoleLog : {
+    level : [Error, Warning, Info, Debug],
+    # e.g. "console-api" for console.* calls, "javascript" for uncaught exceptions, "network" for failed requests
+    source : Str,
+    message : Str,
+    # unix time in milliseconds
+    timestamp : U64,
+}
+
+# The host returns every console log of the session collected so far - not only the new ones.
+get_console_logs! : Str => Result (List ConsoleLog) [WebDriverError Str]
+get_console_logs! = |session_id|
+    logs_json = Effect.browser_get_console_logs!(session_id) |> Result.map_err(WebDriverError)?
+
+    decoded : Result (List { level : Str, source : Str, message : Str, timestamp : F64 }) _
+    decoded = Decode.from_bytes(logs_json |> Str.to_utf8, JsonDecoder.json)
+
+    when decoded is
+        Ok(logs) -> logs |> List.map(to_console_log) |> Ok
+        Err(_) -> Err(WebDriverError("could not decode the console logs: ${logs_json}"))
+
+# Uncaught exceptions and console.error messages, without the ones matching any of the `allow_list` regex patterns.
+# The regex matching is done by the host.
+get_js_errors! : Str, List Str => Result (List Str) [WebDriverError Str]
+get_js_errors! = |session_id, allow_list|
+    allow_list_json = "[${allow_list |> List.map(EncodeDecode.encode_json_string) |> Str.join_with(",")}]"
+
+    Effect.browser_get_js_errors!(session_id, allow_list_json) |> Result.map_err(WebDriverError)
+
+to_console_log = |{ level, source, message, timestamp }|
+    {
+        level: level_from_str(level),
+        source,
+        message,
+        timestamp: timestamp |> Num.floor,
+    }
+
+level_from_str = |level|
+    when level is
+        "SEVERE" -> Error
+        "WARNING" -> Warning
+        "INFO" -> Info
+        _ -> Debug
+
+expect level_from_str("SEVERE") == Error
+expect level_from_str("WARNING") == Warning
+expect level_from_str("FINE") == Debug
+expect to_console_log({ level: "INFO", source: "console-api", message: "hello", timestamp: 1700000000000.0 }) == { level: Info, source: "console-api", message: "hello", timestamp: 1700000000000 }
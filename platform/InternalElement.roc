@@ -1,17 +1,17 @@
-module [get_text!, get_property!, is_visible!]
+module [get_text!, get_property!, is_visible!, is_enabled!, get_accessible_role!, get_accessible_name!, is_focused!]
 
 import Internal exposing [Element]
 import InternalError
 import Effect
 import PropertyDecoder
 
-get_text! : Element => Result Str [WebDriverError Str, ElementNotFound Str]
+get_text! : Element => Result Str [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
 get_text! = |element|
     { session_id, element_id } = Internal.unpack_element_data(element)
 
     Effect.element_get_text!(session_id, element_id) |> Result.map_err(InternalError.handle_element_error)
 
-get_property! : Internal.Element, Str => Result a [ElementNotFound Str, PropertyTypeError Str, WebDriverError Str] where a implements Decoding
+get_property! : Internal.Element, Str => Result a [ElementNotFound Str, StaleElementReference Str, PropertyTypeError Str, WebDriverError Str] where a implements Decoding
 get_property! = |element, property_name|
     { session_id, element_id } = Internal.unpack_element_data(element)
 
@@ -25,7 +25,7 @@ get_property! = |element, property_name|
         Ok(val) -> Ok(val)
         Err(_) -> Err(PropertyTypeError("could not cast property \"${property_name}\" with value \"${result_str}\" to expected type"))
 
-is_visible! : Element => Result [Visible, NotVisible] [WebDriverError Str, ElementNotFound Str]
+is_visible! : Element => Result [Visible, NotVisible] [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
 is_visible! = |element|
     { session_id, element_id } = Internal.unpack_element_data(element)
 
@@ -35,3 +35,37 @@ is_visible! = |element|
         Ok(Visible)
     else
         Ok(NotVisible)
+
+is_enabled! : Element => Result [Enabled, Disabled] [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
+is_enabled! = |element|
+    { session_id, element_id } = Internal.unpack_element_data(element)
+
+    result = Effect.element_is_enabled!(session_id, element_id) |> Result.map_err(InternalError.handle_element_error)?
+
+    if result == "true" then
+        Ok(Enabled)
+    else
+        Ok(Disabled)
+
+get_accessible_role! : Element => Result Str [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
+get_accessible_role! = |element|
+    { session_id, element_id } = Internal.unpack_element_data(element)
+
+    Effect.element_get_computed_role!(session_id, element_id) |> Result.map_err(InternalError.handle_element_error)
+
+get_accessible_name! : Element => Result Str [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
+get_accessible_name! = |element|
+    { session_id, element_id } = Internal.unpack_element_data(element)
+
+    Effect.element_get_computed_label!(session_id, element_id) |> Result.map_err(InternalError.handle_element_error)
+
+is_focused! : Element => Result [Focused, NotFocused] [WebDriverError Str, ElementNotFound Str, StaleElementReference Str]
+is_focused! = |element|
+    { session_id, element_id } = Internal.unpack_element_data(element)
+
+    active_element_id = Effect.browser_get_active_element!(session_id) |> Result.map_err(InternalError.handle_element_error)?
+
+    if active_element_id == element_id then
+        Ok(Focused)
+    else
+        Ok(NotFocused)
@@ -1,16 +1,104 @@
-module [handle_element_error, handle_cookie_error, handle_alert_error]
+module [
+    handle_element_error,
+    handle_interaction_error,
+    handle_find_error,
+    handle_navigation_error,
+    handle_script_error,
+    handle_session_error,
+    handle_frame_error,
+    handle_window_error,
+    handle_cookie_error,
+    handle_alert_error,
+    handle_shadow_root_error,
+    handle_shadow_root_find_error,
+    handle_wait_error,
+]
+
+# The host passes typed W3C errors as "<Tag>::<message>" - e.g. "StaleElementReference::stale element reference: ..."
+# Errors without a known prefix end up as `WebDriverError Str`.
 
 handle_element_error = |err|
     when err is
-        e if e |> Str.starts_with("WebDriverNotFoundError") -> ElementNotFound((e |> Str.drop_prefix("WebDriverNotFoundError::")))
+        e if e |> Str.starts_with("ElementNotFound::") -> ElementNotFound((e |> Str.drop_prefix("ElementNotFound::")))
+        e if e |> Str.starts_with("StaleElementReference::") -> StaleElementReference((e |> Str.drop_prefix("StaleElementReference::")))
+        e -> WebDriverError(e)
+
+handle_interaction_error = |err|
+    when err is
+        e if e |> Str.starts_with("ElementNotFound::") -> ElementNotFound((e |> Str.drop_prefix("ElementNotFound::")))
+        e if e |> Str.starts_with("StaleElementReference::") -> StaleElementReference((e |> Str.drop_prefix("StaleElementReference::")))
+        e if e |> Str.starts_with("ElementClickIntercepted::") -> ElementClickIntercepted((e |> Str.drop_prefix("ElementClickIntercepted::")))
+        e if e |> Str.starts_with("ElementNotInteractable::") -> ElementNotInteractable((e |> Str.drop_prefix("ElementNotInteractable::")))
+        e -> WebDriverError(e)
+
+handle_find_error = |err|
+    when err is
+        e if e |> Str.starts_with("ElementNotFound::") -> ElementNotFound((e |> Str.drop_prefix("ElementNotFound::")))
+        e if e |> Str.starts_with("StaleElementReference::") -> StaleElementReference((e |> Str.drop_prefix("StaleElementReference::")))
+        e if e |> Str.starts_with("InvalidSelector::") -> InvalidSelector((e |> Str.drop_prefix("InvalidSelector::")))
+        e -> WebDriverError(e)
+
+handle_wait_error = |err|
+    when err is
+        e if e |> Str.starts_with("Timeout::") -> Timeout((e |> Str.drop_prefix("Timeout::")))
+        e -> WebDriverError(e)
+
+handle_navigation_error = |err|
+    when err is
+        e if e |> Str.starts_with("Timeout::") -> Timeout((e |> Str.drop_prefix("Timeout::")))
+        e if e |> Str.starts_with("UnexpectedAlertOpen::") -> UnexpectedAlertOpen((e |> Str.drop_prefix("UnexpectedAlertOpen::")))
+        e -> WebDriverError(e)
+
+handle_script_error = |err|
+    when err is
+        e if e |> Str.starts_with("Timeout::") -> Timeout((e |> Str.drop_prefix("Timeout::")))
+        e if e |> Str.starts_with("JsReturnTypeError::") -> JsReturnTypeError((e |> Str.drop_prefix("JsReturnTypeError::")))
+        e -> WebDriverError(e)
+
+handle_session_error = |err|
+    when err is
+        e if e |> Str.starts_with("SessionNotCreated::") -> SessionNotCreated((e |> Str.drop_prefix("SessionNotCreated::")))
+        e -> WebDriverError(e)
+
+handle_frame_error = |err|
+    when err is
+        e if e |> Str.starts_with("NoSuchFrame::") -> NoSuchFrame((e |> Str.drop_prefix("NoSuchFrame::")))
+        e -> WebDriverError(e)
+
+handle_window_error = |err|
+    when err is
+        e if e |> Str.starts_with("NoSuchWindow::") -> NoSuchWindow((e |> Str.drop_prefix("NoSuchWindow::")))
         e -> WebDriverError(e)
 
 handle_alert_error = |err|
     when err is
-        e if e |> Str.starts_with("WebDriverNotFoundError") -> AlertNotFound((e |> Str.drop_prefix("WebDriverNotFoundError::")))
+        e if e |> Str.starts_with("AlertNotFound::") -> AlertNotFound((e |> Str.drop_prefix("AlertNotFound::")))
         e -> WebDriverError(e)
 
 handle_cookie_error = |err|
     when err is
-        e if e |> Str.starts_with("WebDriverNotFoundError") -> CookieNotFound((e |> Str.drop_prefix("WebDriverNotFoundError::")))
+        e if e |> Str.starts_with("CookieNotFound::") -> CookieNotFound((e |> Str.drop_prefix("CookieNotFound::")))
+        e -> WebDriverError(e)
+
+handle_shadow_root_error = |err|
+    when err is
+        e if e |> Str.starts_with("ElementNotFound::") -> ElementNotFound((e |> Str.drop_prefix("ElementNotFound::")))
+        e if e |> Str.starts_with("StaleElementReference::") -> StaleElementReference((e |> Str.drop_prefix("StaleElementReference::")))
+        e if e |> Str.starts_with("ShadowRootNotFound::") -> ShadowRootNotFound((e |> Str.drop_prefix("ShadowRootNotFound::")))
         e -> WebDriverError(e)
+
+handle_shadow_root_find_error = |err|
+    when err is
+        e if e |> Str.starts_with("ElementNotFound::") -> ElementNotFound((e |> Str.drop_prefix("ElementNotFound::")))
+        e if e |> Str.starts_with("InvalidSelector::") -> InvalidSelector((e |> Str.drop_prefix("InvalidSelector::")))
+        e if e |> Str.starts_with("DetachedShadowRoot::") -> DetachedShadowRoot((e |> Str.drop_prefix("DetachedShadowRoot::")))
+        e -> WebDriverError(e)
+
+expect handle_element_error("ElementNotFound::no such element") == ElementNotFound("no such element")
+expect handle_element_error("StaleElementReference::stale element reference") == StaleElementReference("stale element reference")
+expect handle_element_error("WebDriverRequest[500]: unknown error: oops") == WebDriverError("WebDriverRequest[500]: unknown error: oops")
+expect handle_interaction_error("ElementClickIntercepted::element click intercepted") == ElementClickIntercepted("element click intercepted")
+expect handle_find_error("InvalidSelector::invalid selector") == InvalidSelector("invalid selector")
+expect handle_script_error("JsReturnTypeError::expected an element") == JsReturnTypeError("expected an element")
+expect handle_shadow_root_error("ShadowRootNotFound::no such shadow root") == ShadowRootNotFound("no such shadow root")
+expect handle_shadow_root_find_error("DetachedShadowRoot::detached shadow root") == DetachedShadowRoot("detached shadow root")
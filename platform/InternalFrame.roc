@@ -0,0 +1,48 @@
+module [use_frame!]
+
+import Internal exposing [Browser]
+import InternalError
+import Effect
+import Debug
+import DebugMode
+
+# Runs the callback after `switch!` moved the session into a frame,
+# then restores the frame the session was in before - also when the callback failed deeper in the frame tree.
+use_frame! : Str, Str, ({} => Result {} [NoSuchFrame Str, WebDriverError Str]err), (Browser => Result {} _) => Result {} _
+use_frame! = |session_id, frame_text, switch!, callback!|
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Switching context to ${frame_text}"),
+    )
+
+    depth = Effect.get_frame_depth!(session_id)
+
+    switch!({})?
+
+    DebugMode.run_if_debug_mode!(
+        |{}|
+            DebugMode.show_debug_message_in_browser!(session_id, "Switched to ${frame_text}")?
+            DebugMode.flash_current_frame!(session_id)?
+            DebugMode.wait!({})
+            Ok({}),
+    )
+
+    browser = Internal.pack_browser_data({ session_id })
+    result = callback!(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Switching back from ${frame_text}"),
+    )
+
+    Effect.restore_frame_depth!(session_id, depth) |> Result.map_err(InternalError.handle_frame_error)?
+
+    DebugMode.run_if_debug_mode!(
+        |{}|
+            DebugMode.show_debug_message_in_browser!(session_id, "Switched back from ${frame_text}")?
+            DebugMode.flash_current_frame!(session_id)?
+            DebugMode.wait!({})
+            Ok({}),
+    )
+
+    result
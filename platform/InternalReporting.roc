@@ -2,6 +2,7 @@
 module [run_reporters!, ReporterCallback, ReporterDefinition, TestRunResult, TestRunMetadata]
 
 import Fs
+import InternalConsole exposing [ConsoleLog]
 
 TestRunResult err : {
     name : Str,
@@ -9,6 +10,8 @@ TestRunResult err : {
     result : Result {} []err,
     screenshot : [NoScreenshot, Screenshot Str],
     logs : List Str,
+    console_logs : List ConsoleLog,
+    project : [NoProject, Project Str],
     type : [FinalResult, Attempt],
 } where err implements Inspect
 
@@ -5,8 +5,9 @@ import Debug
 import Utils
 import Browser
 import InternalReporting
-import Config exposing [R2EConfiguration]
+import Config exposing [R2EConfiguration, NetworkConditions, Device]
 import Error
+import InternalConsole exposing [ConsoleLog]
 
 # import Assert # without an even number of imports in this module, Roc compiler fails
 
@@ -18,6 +19,10 @@ ConfigOverride : {
     window_size : [Inherit, Override [Size U64 U64]],
     screenshot_on_fail : [Inherit, Override [Yes, No]],
     attempts : [Inherit, Override U64],
+    fail_on_js_errors : [Inherit, Override [Yes, No]],
+    js_errors_allow_list : [Inherit, Override (List Str)],
+    network_conditions : [Inherit, Override NetworkConditions],
+    device : [Inherit, Override Device],
 }
 
 TestBody err : Browser => Result {} [WebDriverError Str]err
@@ -34,6 +39,8 @@ TestCaseResult err : {
     duration : U64,
     screenshot : [NoScreenshot, Screenshot Str],
     logs : List Str,
+    console_logs : List ConsoleLog,
+    project : [NoProject, Project Str],
     type : [FinalResult, Attempt],
 } where err implements Inspect
 
@@ -50,11 +57,15 @@ test = |name, test_body|
                 window_size: Inherit,
                 screenshot_on_fail: Inherit,
                 attempts: Inherit,
+                fail_on_js_errors: Inherit,
+                js_errors_allow_list: Inherit,
+                network_conditions: Inherit,
+                device: Inherit,
             },
         },
     )
 
-test_with = |{ assert_timeout ?? Inherit, page_load_timeout ?? Inherit, script_execution_timeout ?? Inherit, element_implicit_timeout ?? Inherit, window_size ?? Inherit, screenshot_on_fail ?? Inherit, attempts ?? Inherit }|
+test_with = |{ assert_timeout ?? Inherit, page_load_timeout ?? Inherit, script_execution_timeout ?? Inherit, element_implicit_timeout ?? Inherit, window_size ?? Inherit, screenshot_on_fail ?? Inherit, attempts ?? Inherit, fail_on_js_errors ?? Inherit, js_errors_allow_list ?? Inherit, network_conditions ?? Inherit, device ?? Inherit }|
     |name, test_body|
         @TestCase(
             {
@@ -68,6 +79,10 @@ test_with = |{ assert_timeout ?? Inherit, page_load_timeout ?? Inherit, script_e
                     window_size,
                     screenshot_on_fail,
                     attempts,
+                    fail_on_js_errors,
+                    js_errors_allow_list,
+                    network_conditions,
+                    device,
                 },
             },
         )
@@ -81,29 +96,41 @@ run_tests! = |test_cases, config|
     test_filter = Utils.get_test_name_filter!({})
     print_filter_warning!(test_filter)
 
+    project_filter = Utils.get_project_filter!({})
+    print_project_filter_warning!(project_filter)
+
     start_time = Utils.get_time_milis!({})
 
     filtered_test_cases =
         test_cases
         |> List.keep_if(filter_test_case(test_filter))
 
+    # every test runs once per project - all tests of a project run before the next project
+    test_runs =
+        if config.projects |> List.is_empty then
+            filtered_test_cases |> List.map(|test_case| { test_case, project: NoProject })
+        else
+            config.projects
+            |> List.keep_if(filter_project(project_filter))
+            |> List.join_map(|project| filtered_test_cases |> List.map(|test_case| { test_case, project: Project(project) }))
+
     results = loop!(
-        { results_l: [], test_cases_l: filtered_test_cases, index_l: 0, attempt: 1 },
-        |{ results_l, test_cases_l, index_l, attempt }|
-            when test_cases_l is
+        { results_l: [], test_runs_l: test_runs, index_l: 0, attempt: 1 },
+        |{ results_l, test_runs_l, index_l, attempt }|
+            when test_runs_l is
                 [] -> Done(results_l)
-                [test_case, .. as rest] ->
+                [{ test_case, project }, .. as rest] ->
                     # TODO better tests
                     number_of_attempts = get_or_override_attempts(config, test_case)
 
-                    res = run_test!(index_l, attempt, test_case, config)
+                    res = run_test!(index_l, attempt, test_case, project, config)
                     if res.result |> Result.is_ok then
-                        Step({ results_l: List.append(results_l, res), test_cases_l: rest, index_l: index_l + 1, attempt: 1 })
+                        Step({ results_l: List.append(results_l, res), test_runs_l: rest, index_l: index_l + 1, attempt: 1 })
                     else if attempt < number_of_attempts then
                         attempt_res = { res & type: Attempt }
-                        Step({ results_l: List.append(results_l, attempt_res), test_cases_l: test_cases_l, index_l: index_l, attempt: attempt + 1 })
+                        Step({ results_l: List.append(results_l, attempt_res), test_runs_l: test_runs_l, index_l: index_l, attempt: attempt + 1 })
                     else
-                        Step({ results_l: List.append(results_l, res), test_cases_l: rest, index_l: index_l + 1, attempt: 1 }),
+                        Step({ results_l: List.append(results_l, res), test_runs_l: rest, index_l: index_l + 1, attempt: 1 }),
     )
 
     end_time = Utils.get_time_milis!({})
@@ -130,20 +157,34 @@ loop! = |initial_state, callback!|
         Done(result) -> result
         Step(result) -> loop!(result, callback!)
 
-run_test! : U64, U64, TestCase _, R2EConfiguration _ => TestCaseResult [WebDriverError Str, AssertionError Str]_
-run_test! = |i, attempt, @TestCase({ name, test_body, config: test_config_override }), config|
+run_test! : U64, U64, TestCase _, [NoProject, Project _], R2EConfiguration _ => TestCaseResult [WebDriverError Str, AssertionError Str]_
+run_test! = |i, attempt, @TestCase({ name, test_body, config: test_config_override }), test_project, config|
     index_str = (i + 1) |> Num.to_str
 
+    # the project overrides go first - the test overrides take precedence
+    { project, project_str } =
+        when test_project is
+            NoProject -> { project: NoProject, project_str: "" }
+            Project(p) ->
+                p.window_size |> run_if_override!(Utils.set_window_size_override!)
+                p.device |> run_if_override!(Utils.set_device_override!)
+                p.locale |> run_if_override!(Utils.set_locale_override!)
+                p.headless |> run_if_override!(Utils.set_headless_override!)
+                { project: Project(p.name), project_str: " [${p.name}]" }
+
     test_config_override.assert_timeout |> run_if_override!(Utils.set_assert_timeout_override!)
     test_config_override.page_load_timeout |> run_if_override!(Utils.set_page_load_timeout_override!)
     test_config_override.script_execution_timeout |> run_if_override!(Utils.set_script_timeout_override!)
     test_config_override.element_implicit_timeout |> run_if_override!(Utils.set_implicit_timeout_override!)
     test_config_override.window_size |> run_if_override!(Utils.set_window_size_override!)
+    test_config_override.network_conditions |> run_if_override!(Utils.set_network_conditions_override!)
+    test_config_override.device |> run_if_override!(Utils.set_device_override!)
 
     merged_config =
-        when test_config_override.screenshot_on_fail is
-            Override(val) -> { config & screenshot_on_fail: val }
-            Inherit -> config
+        config
+        |> merge_override(test_config_override.screenshot_on_fail, |c, val| { c & screenshot_on_fail: val })
+        |> merge_override(test_config_override.fail_on_js_errors, |c, val| { c & fail_on_js_errors: val })
+        |> merge_override(test_config_override.js_errors_allow_list, |c, val| { c & js_errors_allow_list: val })
 
     attempt_str =
         if attempt > 1 then
@@ -152,12 +193,12 @@ run_test! = |i, attempt, @TestCase({ name, test_body, config: test_config_overri
             ""
 
     Debug.print_line!("") # empty line for readability
-    Debug.print_line!("${color.gray}Test ${index_str}:${color.end} \"${name}\"${attempt_str}: Running...")
+    Debug.print_line!("${color.gray}Test ${index_str}:${color.end} \"${name}\"${project_str}${attempt_str}: Running...")
 
     Utils.reset_test_log_bucket!({})
 
     start_time = Utils.get_time_milis!({})
-    result_with_maybe_screenshot = run_test_safe!(test_body, merged_config)
+    { result: result_with_maybe_screenshot, console_logs } = run_test_safe!(test_body, merged_config)
 
     end_time = Utils.get_time_milis!({})
     duration = end_time - start_time
@@ -178,43 +219,69 @@ run_test! = |i, attempt, @TestCase({ name, test_body, config: test_config_overri
         duration,
         screenshot,
         logs: test_logs,
+        console_logs,
+        project,
         type: FinalResult,
     }
 
     result_log_message =
         when result is
             Ok({}) ->
-                "${color.gray}Test ${index_str}:${color.end} \"${name}\": ${color.green}OK${color.end}"
+                "${color.gray}Test ${index_str}:${color.end} \"${name}\"${project_str}: ${color.green}OK${color.end}"
 
             Err(err) ->
                 when Error.web_driver_error_to_str(err) is
                     StringError(str_err) ->
-                        "${color.gray}Test ${index_str}:${color.end} \"${name}\": ${color.red}${str_err}${color.end}"
+                        "${color.gray}Test ${index_str}:${color.end} \"${name}\"${project_str}: ${color.red}${str_err}${color.end}"
 
                     unhandled_error ->
-                        "${color.gray}Test ${index_str}:${color.end} \"${name}\": ${color.red}${unhandled_error |> Inspect.to_str}${color.end}"
+                        "${color.gray}Test ${index_str}:${color.end} \"${name}\"${project_str}: ${color.red}${unhandled_error |> Inspect.to_str}${color.end}"
 
     Debug.print_line!(result_log_message)
 
     test_case_result
 
 run_test_safe! = |test_body!, config|
-    browser = Browser.open_new_window!({}) |> Result.map_err(ResultWithoutScreenshot)?
+    when Browser.open_new_window!({}) is
+        Ok(browser) -> run_test_in_browser!(test_body!, browser, config)
+        Err(err) -> { result: Err(ResultWithoutScreenshot(err)), console_logs: [] }
 
-    test_result = test_body!(browser)
+run_test_in_browser! = |test_body!, browser, config|
+    test_result =
+        when test_body!(browser) is
+            Ok({}) if config.fail_on_js_errors == Yes -> check_js_errors!(browser, config.js_errors_allow_list)
+            result -> result
 
     should_take_screenshot = (test_result |> Result.is_err) and (config.screenshot_on_fail == Yes)
     screenshot_result = should_take_screenshot |> take_conditional_screenshot!(browser)
 
-    Browser.close_window!(browser) |> Result.map_err(ResultWithoutScreenshot)?
+    # the logs are gone with the session - failing to get them should not fail the test
+    console_logs = browser |> Browser.get_console_logs! |> Result.with_default([])
+
+    result =
+        when Browser.close_window!(browser) is
+            Err(err) -> Err(ResultWithoutScreenshot(err))
+            Ok({}) ->
+                when test_result is
+                    Ok({}) -> Ok({})
+                    Err(res) ->
+                        when screenshot_result is
+                            NoScreenshot -> Err(ResultWithoutScreenshot(res))
+                            ScreenshotBase64(screenshot) -> Err(ResultWithScreenshot(res, screenshot))
+                            err -> Err(ResultWithoutScreenshot(err))
+
+    { result, console_logs }
+
+check_js_errors! = |browser, allow_list|
+    { session_id } = Internal.unpack_browser_data(browser)
 
-    when test_result is
-        Ok({}) -> Ok({})
-        Err(res) ->
-            when screenshot_result is
-                NoScreenshot -> Err(ResultWithoutScreenshot(res))
-                ScreenshotBase64(screenshot) -> Err(ResultWithScreenshot(res, screenshot))
-                err -> Err(ResultWithoutScreenshot(err))
+    when InternalConsole.get_js_errors!(session_id, allow_list) is
+        Ok([]) -> Ok({})
+        Ok(js_errors) ->
+            errors_str = js_errors |> List.map(|msg| "\n- ${msg}") |> Str.join_with("")
+            Err(JsError("Uncaught JavaScript errors on the page:${errors_str}"))
+
+        Err(err) -> Err(err)
 
 take_conditional_screenshot! : Bool, Browser => [ScreenshotBase64 Str, NoScreenshot, WebDriverError Str]
 take_conditional_screenshot! = |should_take_screenshot, browser|
@@ -258,6 +325,11 @@ print_filter_warning! = |test_filter|
         FilterTests(str) -> Debug.print_line!("\n${color.yellow}FILTER: running only tests containing the str: \"${str}\"${color.end}")
         NoFilter -> {}
 
+print_project_filter_warning! = |project_filter|
+    when project_filter is
+        FilterProjects(str) -> Debug.print_line!("\n${color.yellow}FILTER: running only projects containing the str: \"${str}\"${color.end}")
+        NoFilter -> {}
+
 get_or_override_attempts = |main_config, @TestCase(test_case)|
     when test_case.config.attempts is
         Inherit -> main_config.attempts
@@ -269,6 +341,17 @@ filter_test_case = |filter|
             FilterTests(str) -> name |> Str.contains(str)
             NoFilter -> Bool.true
 
+filter_project = |filter|
+    |{ name }|
+        when filter is
+            FilterProjects(str) -> name |> Str.contains(str)
+            NoFilter -> Bool.true
+
+merge_override = |config, value, update|
+    when value is
+        Override(val) -> update(config, val)
+        Inherit -> config
+
 run_if_override! = |value, task!|
     when value is
         Override(val) -> task!(val)
@@ -0,0 +1,417 @@
+# The implementation is based on https://github.com/lukewilliamboswell/roc-json
+# this decoder decodes JSON documents - numbers, strings, booleans, lists, records and tuples
+# record fields are matched by name, or by the snake_case version of a camelCase name
+module [
+    Json,
+    json,
+]
+
+Json := {}
+    implements [
+        DecoderFormatting {
+            u8: decode_u8,
+            u16: decode_u16,
+            u32: decode_u32,
+            u64: decode_u64,
+            u128: decode_u128,
+            i8: decode_i8,
+            i16: decode_i16,
+            i32: decode_i32,
+            i64: decode_i64,
+            i128: decode_i128,
+            f32: decode_f32,
+            f64: decode_f64,
+            dec: decode_dec,
+            bool: decode_bool,
+            string: decode_string,
+            list: decode_list,
+            record: decode_record,
+            tuple: decode_tuple,
+        },
+    ]
+
+json = @Json({})
+
+# NUMBERS ----------------------------------------------------------------------
+
+decode_number : (Str -> Result num _) -> Decoder num Json
+decode_number = |parse|
+    Decode.custom(
+        |bytes, @Json({})|
+            { taken, rest } = bytes |> skip_whitespace |> take_number
+
+            when taken |> Str.from_utf8 |> Result.try(parse) is
+                Ok(num) -> { result: Ok(num), rest }
+                Err(_) -> { result: Err(TooShort), rest: bytes },
+    )
+
+decode_u8 = decode_number(Str.to_u8)
+decode_u16 = decode_number(Str.to_u16)
+decode_u32 = decode_number(Str.to_u32)
+decode_u64 = decode_number(Str.to_u64)
+decode_u128 = decode_number(Str.to_u128)
+decode_i8 = decode_number(Str.to_i8)
+decode_i16 = decode_number(Str.to_i16)
+decode_i32 = decode_number(Str.to_i32)
+decode_i64 = decode_number(Str.to_i64)
+decode_i128 = decode_number(Str.to_i128)
+decode_f32 = decode_number(Str.to_f32)
+decode_f64 = decode_number(Str.to_f64)
+decode_dec = decode_number(Str.to_dec)
+
+take_number : List U8 -> { taken : List U8, rest : List U8 }
+take_number = |bytes|
+    count =
+        bytes
+        |> List.walk_until(
+            0,
+            |len, byte|
+                if is_number_byte(byte) then
+                    Continue(len + 1)
+                else
+                    Break(len),
+        )
+
+    { before: taken, others: rest } = bytes |> List.split_at(count)
+
+    { taken, rest }
+
+is_number_byte : U8 -> Bool
+is_number_byte = |byte|
+    (byte >= '0' and byte <= '9') or byte == '-' or byte == '+' or byte == '.' or byte == 'e' or byte == 'E'
+
+expect
+    actual : DecodeResult U64
+    actual = " 42, 1" |> Str.to_utf8 |> Decode.from_bytes_partial(json)
+    actual == { result: Ok(42), rest: Str.to_utf8(", 1") }
+
+expect
+    actual : DecodeResult F64
+    actual = "-12.5" |> Str.to_utf8 |> Decode.from_bytes_partial(json)
+    actual.result == Ok(-12.5)
+
+expect
+    actual : DecodeResult U8
+    actual = "\"1\"" |> Str.to_utf8 |> Decode.from_bytes_partial(json)
+    actual.result == Err(TooShort)
+
+# BOOLEANS ---------------------------------------------------------------------
+
+decode_bool = Decode.custom(
+    |bytes, @Json({})|
+        when bytes |> skip_whitespace is
+            ['t', 'r', 'u', 'e', .. as rest] -> { result: Ok(Bool.true), rest }
+            ['f', 'a', 'l', 's', 'e', .. as rest] -> { result: Ok(Bool.false), rest }
+            _ -> { result: Err(TooShort), rest: bytes },
+)
+
+expect
+    actual = "true" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok(Bool.true)
+
+expect
+    actual = "false" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok(Bool.false)
+
+# STRINGS ----------------------------------------------------------------------
+
+decode_string = Decode.custom(
+    |bytes, @Json({})|
+        when bytes |> skip_whitespace is
+            ['"', .. as after_quote] ->
+                when take_string(after_quote, []) is
+                    Ok({ str_bytes, rest }) ->
+                        when Str.from_utf8(str_bytes) is
+                            Ok(str) -> { result: Ok(str), rest }
+                            Err(_) -> { result: Err(TooShort), rest: bytes }
+
+                    Err(_) -> { result: Err(TooShort), rest: bytes }
+
+            _ -> { result: Err(TooShort), rest: bytes },
+)
+
+# takes the bytes of a JSON string (after the opening quote) and unescapes them
+take_string : List U8, List U8 -> Result { str_bytes : List U8, rest : List U8 } [TooShort]
+take_string = |bytes, acc|
+    when bytes is
+        [] -> Err(TooShort)
+        ['"', .. as rest] -> Ok({ str_bytes: acc, rest })
+        [0x5c, 'u', a, b, c, d, .. as rest] ->
+            code_point = hex_to_u32([a, b, c, d])?
+
+            if code_point >= 0xD800 and code_point <= 0xDBFF then
+                # surrogate pair
+                when rest is
+                    [0x5c, 'u', e, f, g, h, .. as rest_after_pair] ->
+                        low = hex_to_u32([e, f, g, h])?
+                        combined = 0x10000 + ((code_point - 0xD800) * 0x400) + (low - 0xDC00)
+                        take_string(rest_after_pair, acc |> List.concat(code_point_to_utf8(combined)))
+
+                    _ -> Err(TooShort)
+            else
+                take_string(rest, acc |> List.concat(code_point_to_utf8(code_point)))
+
+        [0x5c, escaped, .. as rest] ->
+            unescaped =
+                when escaped is
+                    'b' -> Ok(0x08)
+                    'f' -> Ok(0x0c)
+                    'n' -> Ok(0x0a)
+                    'r' -> Ok(0x0d)
+                    't' -> Ok(0x09)
+                    '"' | 0x5c | '/' -> Ok(escaped)
+                    _ -> Err(TooShort)
+
+            take_string(rest, acc |> List.append(unescaped?))
+
+        [byte, .. as rest] -> take_string(rest, acc |> List.append(byte))
+
+hex_to_u32 : List U8 -> Result U32 [TooShort]
+hex_to_u32 = |hex_bytes|
+    hex_bytes
+    |> List.walk_try(
+        0,
+        |acc, byte|
+            digit =
+                if byte >= '0' and byte <= '9' then
+                    Ok(byte - '0')
+                else if byte >= 'a' and byte <= 'f' then
+                    Ok(byte - 'a' + 10)
+                else if byte >= 'A' and byte <= 'F' then
+                    Ok(byte - 'A' + 10)
+                else
+                    Err(TooShort)
+
+            digit |> Result.map_ok(|d| (acc * 16) + Num.to_u32(d)),
+    )
+
+code_point_to_utf8 : U32 -> List U8
+code_point_to_utf8 = |cp|
+    if cp < 0x80 then
+        [Num.to_u8(cp)]
+    else if cp < 0x800 then
+        [
+            Num.to_u8(Num.bitwise_or(0xC0, Num.shift_right_zf_by(cp, 6))),
+            Num.to_u8(Num.bitwise_or(0x80, Num.bitwise_and(cp, 0x3F))),
+        ]
+    else if cp < 0x10000 then
+        [
+            Num.to_u8(Num.bitwise_or(0xE0, Num.shift_right_zf_by(cp, 12))),
+            Num.to_u8(Num.bitwise_or(0x80, Num.bitwise_and(Num.shift_right_zf_by(cp, 6), 0x3F))),
+            Num.to_u8(Num.bitwise_or(0x80, Num.bitwise_and(cp, 0x3F))),
+        ]
+    else
+        [
+            Num.to_u8(Num.bitwise_or(0xF0, Num.shift_right_zf_by(cp, 18))),
+            Num.to_u8(Num.bitwise_or(0x80, Num.bitwise_and(Num.shift_right_zf_by(cp, 12), 0x3F))),
+            Num.to_u8(Num.bitwise_or(0x80, Num.bitwise_and(Num.shift_right_zf_by(cp, 6), 0x3F))),
+            Num.to_u8(Num.bitwise_or(0x80, Num.bitwise_and(cp, 0x3F))),
+        ]
+
+expect
+    actual = "\"hello\"" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok("hello")
+
+expect
+    actual = "\"a\\\"b\\\\c\\nd\"" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok("a\"b\\c\nd")
+
+expect
+    actual = "\"\\u003cdiv\\u003e \\u00e9 \\ud83d\\ude00\"" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok("<div> é 😀")
+
+# JSON ARRAYS ------------------------------------------------------------------
+
+decode_list : Decoder elem Json -> Decoder (List elem) Json
+decode_list = |elem_decoder|
+    Decode.custom(
+        |bytes, @Json({})|
+            when bytes |> skip_whitespace is
+                ['[', .. as after_bracket] ->
+                    when after_bracket |> skip_whitespace is
+                        [']', .. as rest] -> { result: Ok([]), rest }
+                        _ -> decode_list_elems(after_bracket, elem_decoder, [])
+
+                _ -> { result: Err(TooShort), rest: bytes },
+    )
+
+decode_list_elems : List U8, Decoder elem Json, List elem -> DecodeResult (List elem)
+decode_list_elems = |bytes, elem_decoder, acc|
+    { result, rest } = Decode.decode_with(bytes, elem_decoder, json)
+
+    when result is
+        Ok(elem) ->
+            next_acc = acc |> List.append(elem)
+
+            when rest |> skip_whitespace is
+                [',', .. as after_comma] -> decode_list_elems(after_comma, elem_decoder, next_acc)
+                [']', .. as after_bracket] -> { result: Ok(next_acc), rest: after_bracket }
+                _ -> { result: Err(TooShort), rest }
+
+        Err(err) -> { result: Err(err), rest }
+
+expect
+    actual : Result (List U64) _
+    actual = "[1, 2, 3]" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok([1, 2, 3])
+
+expect
+    actual : Result (List Str) _
+    actual = "[ ]" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok([])
+
+expect
+    actual : Result (List (List Str)) _
+    actual = "[[\"a\"],[\"b\",\"c\"]]" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok([["a"], ["b", "c"]])
+
+# JSON OBJECTS -----------------------------------------------------------------
+
+decode_record : state, (state, Str -> [Keep (Decoder state Json), Skip]), (state, Json -> [Err DecodeError, Ok val]) -> Decoder val Json
+decode_record = |initial_state, step_field, finalizer|
+    Decode.custom(
+        |bytes, @Json({})|
+            when bytes |> skip_whitespace is
+                ['{', .. as after_brace] ->
+                    when after_brace |> skip_whitespace is
+                        ['}', .. as rest] -> { result: finalizer(initial_state, json), rest }
+                        _ -> decode_record_fields(after_brace, initial_state, step_field, finalizer)
+
+                _ -> { result: Err(TooShort), rest: bytes },
+    )
+
+decode_record_fields = |bytes, state, step_field, finalizer|
+    key_result = Decode.decode_with(bytes, decode_string, json)
+
+    when key_result.result is
+        Err(err) -> { result: Err(err), rest: bytes }
+        Ok(key) ->
+            when key_result.rest |> skip_whitespace is
+                [':', .. as after_colon] ->
+                    value_result =
+                        when step_field_by_name(state, key, step_field) is
+                            Skip ->
+                                skip_value(after_colon) |> Result.map_ok(|rest| { next_state: state, rest })
+
+                            Keep(value_decoder) ->
+                                { result, rest } = Decode.decode_with(after_colon, value_decoder, json)
+                                result |> Result.map_ok(|next_state| { next_state, rest })
+
+                    when value_result is
+                        Err(err) -> { result: Err(err), rest: after_colon }
+                        Ok({ next_state, rest }) ->
+                            when rest |> skip_whitespace is
+                                [',', .. as after_comma] -> decode_record_fields(after_comma, next_state, step_field, finalizer)
+                                ['}', .. as after_brace] -> { result: finalizer(next_state, json), rest: after_brace }
+                                _ -> { result: Err(TooShort), rest }
+
+                _ -> { result: Err(TooShort), rest: key_result.rest }
+
+# JS usually uses camelCase - try the snake_case field name when there is no exact match
+step_field_by_name = |state, key, step_field|
+    when step_field(state, key) is
+        Skip -> step_field(state, camel_to_snake_case(key))
+        keep -> keep
+
+camel_to_snake_case : Str -> Str
+camel_to_snake_case = |str|
+    str
+    |> Str.to_utf8
+    |> List.walk(
+        [],
+        |acc, byte|
+            if byte >= 'A' and byte <= 'Z' then
+                acc |> List.append('_') |> List.append(byte + 32)
+            else
+                acc |> List.append(byte),
+    )
+    |> Str.from_utf8
+    |> Result.with_default(str)
+
+expect camel_to_snake_case("firstName") == "first_name"
+expect camel_to_snake_case("name") == "name"
+
+expect
+    actual : Result { name : Str, price : F64 } _
+    actual = "{\"name\": \"apple\", \"price\": 1.5}" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok({ name: "apple", price: 1.5 })
+
+expect
+    actual : Result { first_name : Str, in_stock : Bool } _
+    actual = "{\"firstName\":\"Ann\",\"ignored\":{\"a\":[1,\"]\"]},\"inStock\":true}" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok({ first_name: "Ann", in_stock: Bool.true })
+
+expect
+    actual : Result (List { id : U64 }) _
+    actual = "[{\"id\":1},{\"id\":2}]" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok([{ id: 1 }, { id: 2 }])
+
+# TUPLES -----------------------------------------------------------------------
+
+decode_tuple : state, (state, U64 -> [Next (Decoder state Json), TooLong]), (state -> [Err DecodeError, Ok val]) -> Decoder val Json
+decode_tuple = |initial_state, step_elem, finalizer|
+    Decode.custom(
+        |bytes, @Json({})|
+            when bytes |> skip_whitespace is
+                ['[', .. as after_bracket] -> decode_tuple_elems(after_bracket, initial_state, 0, step_elem, finalizer)
+                _ -> { result: Err(TooShort), rest: bytes },
+    )
+
+decode_tuple_elems = |bytes, state, index, step_elem, finalizer|
+    when bytes |> skip_whitespace is
+        [']', .. as rest] -> { result: finalizer(state), rest }
+        _ ->
+            when step_elem(state, index) is
+                TooLong -> { result: Err(TooShort), rest: bytes }
+                Next(elem_decoder) ->
+                    { result, rest } = Decode.decode_with(bytes, elem_decoder, json)
+
+                    when result is
+                        Err(err) -> { result: Err(err), rest }
+                        Ok(next_state) ->
+                            when rest |> skip_whitespace is
+                                [',', .. as after_comma] -> decode_tuple_elems(after_comma, next_state, index + 1, step_elem, finalizer)
+                                [']', .. as after_bracket] -> { result: finalizer(next_state), rest: after_bracket }
+                                _ -> { result: Err(TooShort), rest }
+
+expect
+    actual : Result (Str, U64) _
+    actual = "[\"a\", 1]" |> Str.to_utf8 |> Decode.from_bytes(json)
+    actual == Ok(("a", 1))
+
+# HELPERS ----------------------------------------------------------------------
+
+skip_whitespace : List U8 -> List U8
+skip_whitespace = |bytes|
+    when bytes is
+        [byte, .. as rest] if byte == ' ' or byte == 0x0a or byte == 0x0d or byte == 0x09 -> skip_whitespace(rest)
+        _ -> bytes
+
+# skips any JSON value - used for fields that are not in the target record
+skip_value : List U8 -> Result (List U8) [TooShort]
+skip_value = |bytes|
+    when bytes |> skip_whitespace is
+        ['"', .. as after_quote] -> take_string(after_quote, []) |> Result.map_ok(.rest)
+        ['{', .. as rest] | ['[', .. as rest] -> skip_nested(rest, 1)
+        trimmed -> Ok(skip_scalar(trimmed))
+
+skip_nested : List U8, U64 -> Result (List U8) [TooShort]
+skip_nested = |bytes, depth|
+    if depth == 0 then
+        Ok(bytes)
+    else
+        when bytes is
+            [] -> Err(TooShort)
+            ['"', .. as after_quote] ->
+                { rest } = take_string(after_quote, [])?
+                skip_nested(rest, depth)
+
+            ['{', .. as rest] | ['[', .. as rest] -> skip_nested(rest, depth + 1)
+            ['}', .. as rest] | [']', .. as rest] -> skip_nested(rest, depth - 1)
+            [_, .. as rest] -> skip_nested(rest, depth)
+
+skip_scalar : List U8 -> List U8
+skip_scalar = |bytes|
+    when bytes is
+        [byte, .. as rest] if byte != ',' and byte != '}' and byte != ']' and byte != ' ' and byte != 0x0a and byte != 0x0d and byte != 0x09 -> skip_scalar(rest)
+        _ -> bytes
@@ -0,0 +1,294 @@
+## `Network` module contains functions to block, stub and modify
+## the requests made by the page - e.g. to test the error states
+## of a frontend without a misbehaving backend.
+##
+## The requests are intercepted with the Chrome DevTools Protocol,
+## so this module is only supported in a local Chrome - not with a remote
+## driver (`--driver-url`).
+##
+## The interception applies to the window that was current
+## when the first route was added in the test.
+##
+## URL patterns are globs - `*` matches any characters and `?` matches
+## a single character. When more routes match a request, then the most
+## recently added one is used.
+##
+## When an intercepted request could not be handled, the next route
+## function (e.g. `mock_response!` or `clear_routes!`) returns the error.
+##
+## The requests of the test window are recorded from the start of the test,
+## so `wait_for_response!` and `get_requests!` also see the requests
+## made before they were called.
+module [
+    Header,
+    MockBody,
+    MockResponse,
+    RequestOverride,
+    NetworkRequest,
+    NetworkResponse,
+    block_urls!,
+    mock_response!,
+    modify_request!,
+    clear_routes!,
+    wait_for_response!,
+    get_requests!,
+]
+
+import Effect
+import EncodeDecode
+import JsonDecoder
+import Internal exposing [Browser]
+import Debug
+import DebugMode
+import Utils
+
+Header : { name : Str, value : Str }
+
+MockBody : [Text Str, File Str]
+
+MockResponse : {
+    status ?? U16,
+    headers ?? List Header,
+    body ?? MockBody,
+}
+
+RequestOverride : {
+    url ?? Str,
+    method ?? Str,
+    headers ?? List Header,
+    post_data ?? Str,
+}
+
+NetworkRequest : {
+    url : Str,
+    method : Str,
+    headers : List Header,
+    post_data : Str,
+    # e.g. "Document", "Fetch", "XHR", "Script", "Image"
+    resource_type : Str,
+    response : [Pending, Received { status : U16, headers : List Header }, Failed Str],
+}
+
+NetworkResponse : {
+    url : Str,
+    status : U16,
+    status_text : Str,
+    headers : List Header,
+    body : Str,
+}
+
+## Block all requests matching any of the URL patterns.
+##
+## The blocked requests fail with a network error.
+##
+## ```
+## browser |> Network.block_urls!(["*.png", "*/analytics/*"])?
+## ```
+block_urls! : Browser, List Str => Result {} [WebDriverError Str]
+block_urls! = |browser, url_patterns|
+    url_patterns
+    |> for_each!(
+        |url_pattern|
+            browser |> add_route!(url_pattern, "\"action\":\"block\""),
+    )
+
+## Respond to all requests matching the URL pattern with a stubbed response.
+##
+## The request never reaches the server.
+##
+## ```
+## MockResponse : {
+##     status ?? U16, # default: 200
+##     headers ?? List { name : Str, value : Str }, # default: []
+##     body ?? [Text Str, File Str], # default: Text("")
+## }
+## ```
+## ```
+## browser |> Network.mock_response!("*/api/users", {
+##     status: 500,
+##     headers: [{ name: "Content-Type", value: "application/json" }],
+##     body: Text("{\"error\": \"internal error\"}"),
+## })?
+##
+## # the body can be read from a file
+## browser |> Network.mock_response!("*/api/users", { body: File("./mocks/users.json") })?
+## ```
+mock_response! : Browser, Str, MockResponse => Result {} [WebDriverError Str]
+mock_response! = |browser, url_pattern, { status ?? 200, headers ?? [], body ?? Text("") }|
+    body_json =
+        when body is
+            Text(text) -> "\"body\":${EncodeDecode.encode_json_string(text)}"
+            File(path) -> "\"bodyFile\":${EncodeDecode.encode_json_string(path)}"
+
+    route_fields = "\"action\":\"fulfill\",\"status\":${status |> Num.to_str},\"headers\":${headers |> headers_to_json},${body_json}"
+
+    browser |> add_route!(url_pattern, route_fields)
+
+## Send all requests matching the URL pattern with the overridden
+## url, method, headers or body.
+##
+## The headers are added to the original headers of the request,
+## replacing the ones with the same name.
+##
+## ```
+## RequestOverride : {
+##     url ?? Str, # default: the original url
+##     method ?? Str, # default: the original method
+##     headers ?? List { name : Str, value : Str }, # default: []
+##     post_data ?? Str, # default: the original body
+## }
+## ```
+## ```
+## browser |> Network.modify_request!("*/api/*", {
+##     headers: [{ name: "Authorization", value: "Bearer test-token" }],
+## })?
+##
+## # use a different backend
+## browser |> Network.modify_request!("https://api.example.com/users", { url: "http://localhost:3000/users" })?
+## ```
+modify_request! : Browser, Str, RequestOverride => Result {} [WebDriverError Str]
+modify_request! = |browser, url_pattern, { url ?? "", method ?? "", headers ?? [], post_data ?? "" }|
+    route_fields =
+        [
+            "\"action\":\"continue\"",
+            "\"url\":${EncodeDecode.encode_json_string(url)}",
+            "\"method\":${EncodeDecode.encode_json_string(method)}",
+            "\"headers\":${headers |> headers_to_json}",
+            "\"postData\":${EncodeDecode.encode_json_string(post_data)}",
+        ]
+        |> Str.join_with(",")
+
+    browser |> add_route!(url_pattern, route_fields)
+
+## Remove all routes - the requests will reach the server again.
+##
+## ```
+## browser |> Network.clear_routes!()?
+## ```
+clear_routes! : Browser => Result {} [WebDriverError Str]
+clear_routes! = |browser|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Clearing network routes"),
+    )
+
+    Effect.network_clear_routes!(session_id) |> Result.map_err(WebDriverError)
+
+## Wait for a response to a request matching the URL pattern.
+##
+## Every response is returned only once - calling this function again
+## waits for the next matching response.
+##
+## Binary bodies are returned with the invalid characters replaced.
+##
+## This function will wait for the **assert_timeout** specified in test options - default: 3s.
+##
+## ```
+## save_button |> Element.click!()?
+##
+## response = browser |> Network.wait_for_response!("*/api/users")?
+## response.status |> Assert.should_be(201)?
+## response.body |> Assert.should_contain_text("\"name\":\"Bob\"")?
+## ```
+wait_for_response! : Browser, Str => Result NetworkResponse [WebDriverError Str, Timeout Str]
+wait_for_response! = |browser, url_pattern|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Waiting for a response from: ${url_pattern}"),
+    )
+
+    timeout = Utils.get_assert_timeout!({})
+    start_time = Utils.get_time_milis!({})
+
+    wait_for_response_loop!(session_id, url_pattern, start_time, timeout)
+
+wait_for_response_loop! = |session_id, url_pattern, start_time, timeout|
+    response_json = Effect.network_take_response!(session_id, url_pattern) |> Result.map_err(WebDriverError)?
+
+    if response_json == "" then
+        now = Utils.get_time_milis!({})
+        if now - start_time >= timeout then
+            Err(Timeout("No response from \"${url_pattern}\" was received (waited for ${timeout |> Num.to_str}ms)"))
+        else
+            Debug.wait!(100)
+            wait_for_response_loop!(session_id, url_pattern, start_time, timeout)
+    else
+        decoded : Result NetworkResponse _
+        decoded = Decode.from_bytes(response_json |> Str.to_utf8, JsonDecoder.json)
+
+        decoded |> Result.map_err(|_| WebDriverError("could not decode the response: ${response_json}"))
+
+## Get all requests made by the page since the start of the test.
+##
+## ```
+## requests = browser |> Network.get_requests!()?
+##
+## analytics_calls = requests |> List.keep_if(|{ url }| url |> Str.contains("/analytics/event"))
+## analytics_calls |> Assert.should_have_length(1)?
+## ```
+get_requests! : Browser => Result (List NetworkRequest) [WebDriverError Str]
+get_requests! = |browser|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    requests_json = Effect.network_get_requests!(session_id) |> Result.map_err(WebDriverError)?
+
+    decoded : Result (List { url : Str, method : Str, headers : List Header, post_data : Str, resource_type : Str, state : Str, status : U16, response_headers : List Header, error_text : Str }) _
+    decoded = Decode.from_bytes(requests_json |> Str.to_utf8, JsonDecoder.json)
+
+    when decoded is
+        Ok(requests) -> requests |> List.map(to_network_request) |> Ok
+        Err(_) -> Err(WebDriverError("could not decode the requests: ${requests_json}"))
+
+to_network_request = |{ url, method, headers, post_data, resource_type, state, status, response_headers, error_text }|
+    response =
+        when state is
+            "finished" -> Received({ status, headers: response_headers })
+            "failed" -> Failed(error_text)
+            _ -> Pending
+
+    { url, method, headers, post_data, resource_type, response }
+
+add_route! : Browser, Str, Str => Result {} [WebDriverError Str]
+add_route! = |browser, url_pattern, route_fields|
+    { session_id } = Internal.unpack_browser_data(browser)
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Adding network route for: ${url_pattern}"),
+    )
+
+    route_json = "{\"urlPattern\":${EncodeDecode.encode_json_string(url_pattern)},${route_fields}}"
+
+    Effect.network_add_route!(session_id, route_json) |> Result.map_err(WebDriverError)
+
+for_each! = |list, callback!|
+    when list is
+        [] -> Ok({})
+        [el, .. as rest] ->
+            callback!(el)?
+            for_each!(rest, callback!)
+
+headers_to_json : List Header -> Str
+headers_to_json = |headers|
+    headers_str =
+        headers
+        |> List.map(
+            |{ name, value }|
+                "{\"name\":${EncodeDecode.encode_json_string(name)},\"value\":${EncodeDecode.encode_json_string(value)}}",
+        )
+        |> Str.join_with(",")
+
+    "[${headers_str}]"
+
+expect headers_to_json([]) == "[]"
+expect headers_to_json([{ name: "a", value: "b" }, { name: "c", value: "d" }]) == "[{\"name\":\"a\",\"value\":\"b\"},{\"name\":\"c\",\"value\":\"d\"}]"
+expect
+    request = to_network_request({ url: "a", method: "GET", headers: [], post_data: "", resource_type: "Fetch", state: "failed", status: 0, response_headers: [], error_text: "net::ERR_FAILED" })
+    request.response == Failed("net::ERR_FAILED")
+expect
+    request = to_network_request({ url: "a", method: "GET", headers: [], post_data: "", resource_type: "Fetch", state: "finished", status: 204, response_headers: [], error_text: "" })
+    request.response == Received({ status: 204, headers: [] })
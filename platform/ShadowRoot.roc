@@ -0,0 +1,140 @@
+## `ShadowRoot` module contains functions to find `Elements`
+## inside of the shadow DOM of an `Element` - e.g. a web component.
+##
+## The shadow root can be accessed with `Element.get_shadow_root!`.
+##
+## The `XPath` locator is not supported inside of a shadow root.
+module [
+    find_element!,
+    try_find_element!,
+    find_single_element!,
+    find_elements!,
+]
+
+import Internal exposing [Element, ShadowRoot]
+import InternalError
+import Common.Locator as Locator
+import Effect
+import Debug
+import DebugMode
+
+## Find an `Element` inside of a `ShadowRoot`.
+##
+## When there are more than 1 elements, then the first will
+## be returned.
+##
+## ```
+## shadow_root = date_picker |> Element.get_shadow_root!()?
+## input = shadow_root |> ShadowRoot.find_element!(Css("input"))?
+## ```
+find_element! : ShadowRoot, Locator.Locator => Result Element [WebDriverError Str, ElementNotFound Str, InvalidSelector Str, DetachedShadowRoot Str]
+find_element! = |shadow_root, locator|
+    { session_id, shadow_root_id, selector_text: host_selector_text } = Internal.unpack_shadow_root_data(shadow_root)
+    (using, value) = Locator.get_locator(locator)
+
+    selector_text = "${locator |> Inspect.to_str} in shadow root of ${host_selector_text}"
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Searching for element: ${selector_text}"),
+    )
+
+    element_id = Effect.shadow_root_find_element!(session_id, shadow_root_id, using, value) |> Result.map_err(InternalError.handle_shadow_root_find_error)?
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Found element: ${selector_text}"),
+    )
+
+    DebugMode.run_if_debug_mode!(
+        |{}|
+            DebugMode.show_debug_message_in_browser!(session_id, "Find Element ${selector_text}")?
+            DebugMode.wait!({})
+            Ok({}),
+    )
+
+    Internal.pack_element_data({ session_id, element_id, selector_text, locator: Locator.to_element_locator(locator) }) |> Ok
+
+## Find an `Element` inside of a `ShadowRoot`.
+##
+## This function returns a `[Found Element, NotFound]` instead of an error
+## when element is not found.
+##
+## ```
+## maybe_error = shadow_root |> ShadowRoot.try_find_element!(Css(".error"))?
+## ```
+try_find_element! : ShadowRoot, Locator.Locator => Result [Found Element, NotFound] [WebDriverError Str, ElementNotFound Str, InvalidSelector Str, DetachedShadowRoot Str]
+try_find_element! = |shadow_root, locator|
+    find_element!(shadow_root, locator)
+    |> Result.map_ok(Found)
+    |> Result.on_err(
+        |err|
+            when err is
+                ElementNotFound(_) -> Ok(NotFound)
+                other -> Err(other),
+    )
+
+## Find an `Element` inside of a `ShadowRoot`.
+##
+## This function will fail if the element is not found - `ElementNotFound Str`
+##
+## This function will fail if there are more than 1 element - `AssertionError Str`
+##
+## ```
+## input = shadow_root |> ShadowRoot.find_single_element!(Css("input"))?
+## ```
+find_single_element! : ShadowRoot, Locator.Locator => Result Element [AssertionError Str, ElementNotFound Str, InvalidSelector Str, DetachedShadowRoot Str, WebDriverError Str]
+find_single_element! = |shadow_root, locator|
+    { selector_text: host_selector_text } = Internal.unpack_shadow_root_data(shadow_root)
+    elements = find_elements!(shadow_root, locator)?
+    when elements |> List.len is
+        0 ->
+            (_, value) = Locator.get_locator(locator)
+            Err(ElementNotFound("element with selector ${value} was not found in shadow root of ${host_selector_text}"))
+
+        1 ->
+            elements
+            |> List.first
+            |> Result.on_err(|_| crash("just checked - there is 1 element in the list"))
+
+        n ->
+            (_, value) = Locator.get_locator(locator)
+            Err(AssertionError("expected to find only 1 element with selector \"${value}\", but found ${n |> Num.to_str}"))
+
+## Find all `Elements` inside of a `ShadowRoot`.
+##
+## When there are no elements found, then the list will be empty.
+##
+## ```
+## options = shadow_root |> ShadowRoot.find_elements!(Css("li"))?
+## ```
+find_elements! : ShadowRoot, Locator.Locator => Result (List Element) [WebDriverError Str, ElementNotFound Str, InvalidSelector Str, DetachedShadowRoot Str]
+find_elements! = |shadow_root, locator|
+    { session_id, shadow_root_id, selector_text: host_selector_text } = Internal.unpack_shadow_root_data(shadow_root)
+    (using, value) = Locator.get_locator(locator)
+
+    selector_text = "${locator |> Inspect.to_str} in shadow root of ${host_selector_text}"
+
+    DebugMode.run_if_verbose!(
+        |{}|
+            Debug.print_line!("Searching for elements: ${selector_text}"),
+    )
+
+    result = Effect.shadow_root_find_elements!(session_id, shadow_root_id, using, value) |> Result.map_err(InternalError.handle_shadow_root_find_error)
+
+    when result is
+        Ok(element_ids) ->
+            DebugMode.run_if_verbose!(
+                |{}|
+                    Debug.print_line!("Found ${element_ids |> List.len |> Num.to_str} elements: ${selector_text}"),
+            )
+
+            element_ids
+            |> List.map(
+                |element_id|
+                    Internal.pack_element_data({ session_id, element_id, selector_text, locator: Locator.to_element_locator(locator) }),
+            )
+            |> Ok
+
+        Err(ElementNotFound(_)) -> Ok([])
+        Err(err) -> Err(err)
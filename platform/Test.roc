@@ -39,6 +39,12 @@ test = InternalTest.test
 ##     window_size : [Inherit, Override [Size U64 U64]],
 ##     screenshot_on_fail : [Inherit, Override [Yes, No]],
 ##     attempts : [Inherit, Override U64],
+##     fail_on_js_errors : [Inherit, Override [Yes, No]],
+##     js_errors_allow_list : [Inherit, Override (List Str)],
+##     # NoThrottling, Offline, Slow3G, Fast3G or Custom - see `Config.R2EConfiguration`
+##     network_conditions : [Inherit, Override NetworkConditions],
+##     # Desktop, IPhoneSE, IPhone14, Pixel7, IPadMini, IPadPro11 or Custom - see `Config.R2EConfiguration`
+##     device : [Inherit, Override Device],
 ## }
 ## ```
 test_with = InternalTest.test_with
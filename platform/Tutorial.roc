@@ -122,8 +122,12 @@
 ## - `--verbose` - verbose logging
 ## - `--debug` - verbose logging, wait between actions, show actions in browser
 ## - `--name somePattern` - filter tests to run by name (useful when writing new tests)
+## - `--project somePattern` - filter projects to run by name (see the Projects chapter)
+## - `--chrome-arg=--disable-gpu` - an extra Chrome argument, added after the `chrome_options` args from the `config` (can be used multiple times)
 ## - `--setup` - run only the browser and driver setup step (useful for CI/CD)
 ## - `--print-browser-version-only` - only prints the version of the used browser (useful for caching in CI/CD)
+## - `--driver-url http://my-grid:4444/wd/hub` - use an already running WebDriver endpoint (e.g. Selenium Grid) instead of downloading and starting the browser locally - can also be set with the `R2E_DRIVER_URL` env variable
+## - `--browser firefox` - run the tests in `chrome` or `firefox`, overriding the `browser` from the `config` - can also be set with the `R2E_BROWSER` env variable (`--setup` and `--print-browser-version-only` also use the `browser` from the `config` when it is not overridden)
 ##
 ## # Config
 ##
@@ -161,6 +165,20 @@
 ##     screenshot_on_fail : Yes
 ##     # number of attempts
 ##     attempts : 2,
+##     # browser to run the tests in - Chrome or Firefox
+##     browser: Chrome,
+##     # should fail a test on uncaught JavaScript errors and console.error messages? (Chrome only)
+##     fail_on_js_errors: No,
+##     # regex patterns of known JavaScript errors that should not fail a test
+##     js_errors_allow_list: [],
+##     # network throttling - NoThrottling, Offline, Slow3G, Fast3G or Custom (Chrome only)
+##     network_conditions: NoThrottling,
+##     # device to emulate - Desktop, IPhoneSE, IPhone14, Pixel7, IPadMini, IPadPro11 or Custom
+##     device: Desktop,
+##     # named configuration variants - every test runs once per project (see the Projects chapter)
+##     projects: [],
+##     # extra Chrome args, prefs, excluded switches and unpacked extensions (see `Config.chrome_options`)
+##     chrome_options: Config.chrome_options({}),
 ## }
 ## ```
 ##
@@ -197,6 +215,16 @@
 ## )
 ## ```
 ##
+## The same checks can run against phone and tablet layouts with device emulation:
+##
+## ```
+## phone_test = Test.test_with({ device: Override(IPhone14) })
+##
+## tablet_test = Test.test_with({
+##     device: Override(Custom({ width: 800, height: 1280, pixel_ratio: 2.0, user_agent: "My Tablet", touch: Bool.true, mobile: Bool.true })),
+## })
+## ```
+##
 ## All possible overrides:
 ## ```
 ## ConfigOverride : {
@@ -207,9 +235,72 @@
 ##     window_size : [Inherit, Override [Size U64 U64]],
 ##     screenshot_on_fail : [Inherit, Override [Yes, No]],
 ##     attempts : [Inherit, Override U64],
+##     fail_on_js_errors : [Inherit, Override [Yes, No]],
+##     js_errors_allow_list : [Inherit, Override (List Str)],
+##     # NoThrottling, Offline, Slow3G, Fast3G or Custom - see `Config.R2EConfiguration`
+##     network_conditions : [Inherit, Override NetworkConditions],
+##     # Desktop, IPhoneSE, IPhone14, Pixel7, IPadMini, IPadPro11 or Custom - see `Config.R2EConfiguration`
+##     device : [Inherit, Override Device],
 ## }
 ## ```
 ##
+## # Downloads
+##
+## Every test has its own download directory in `<results_dir_name>/downloads/`,
+## so the downloads can be checked without the files from the other tests:
+##
+## ```
+## export_button |> Element.click!()?
+##
+## browser |> Assert.file_downloaded!("report-*.csv")?
+##
+## files = browser |> Browser.get_downloads!()?
+## content = browser |> Browser.read_download!("report-2024.csv")? |> Str.from_utf8_lossy
+## content |> Assert.should_contain_text("id,name")?
+## ```
+##
+## Downloads are only supported with a local browser (not with `--driver-url`).
+##
+## When the "download.default_directory" Chrome pref is set in `chrome_options`,
+## all tests save the downloads to that directory instead.
+##
+## # Chrome Options
+##
+## Extra Chrome arguments, preferences, excluded chromedriver switches
+## and unpacked extensions can be set in the `config`:
+##
+## ```
+## config = Config.default_config_with({
+##     chrome_options: Config.chrome_options({
+##         args: ["--disable-gpu"],
+##         prefs: [{ name: "profile.default_content_setting_values.notifications", value: Number(1) }],
+##         exclude_switches: ["enable-automation"],
+##         # e.g. test your browser extension together with the web app
+##         extensions: ["./my-extension/dist"],
+##     }),
+## })
+## ```
+##
+## # Projects
+##
+## Projects run the whole test suite across a matrix of configurations
+## (window size, device emulation, locale, headless) - every test runs once per project:
+##
+## ```
+## config = Config.default_config_with({
+##     projects: [
+##         Config.project("desktop", {}),
+##         Config.project("phone", { device: Override(IPhone14) }),
+##         Config.project("tablet-de", { device: Override(IPadMini), locale: Override("de-DE") }),
+##     ],
+## })
+## ```
+##
+## The project name is shown in the test output and the reports.
+## Use `--project phone` to run only the projects containing "phone" in the name.
+##
+## The overrides from `Test.test_with` take precedence over the project.
+##
 ## # CI/CD
 ##
 ## R2E is designed to be used for automation.
@@ -315,6 +406,10 @@
 ##     screenshot : [NoScreenshot, Screenshot Str],
 ##     # Debug.printLine calls perfomed during this test
 ##     logs : List Str,
+##     # browser console messages and uncaught JavaScript errors (Chrome only)
+##     console_logs : List { level : [Error, Warning, Info, Debug], source : Str, message : Str, timestamp : U64 },
+##     # the project this test ran in
+##     project : [NoProject, Project Str],
 ##     # final result of this test, or just a failed attempt?
 ##     type : [FinalResult, Attempt],
 ## } where err implements Inspect
@@ -329,6 +424,53 @@
 ##
 ## [https://github.com/adomurad/r2e-platform/blob/main/platform/BasicHtmlReporter.roc](https://github.com/adomurad/r2e-platform/blob/main/platform/BasicHtmlReporter.roc)
 ##
+## # Network
+##
+## The `Network` module can block, stub and modify the requests made by the page (Chrome only).
+##
+## This lets you test the error states of your frontend without a misbehaving backend:
+##
+## ```
+## test1 = test(
+##     "shows an error when the api fails",
+##     |browser|
+##         browser |> Network.mock_response!("*/api/users*", { status: 500, body: Text("{}") })?
+##         browser |> Network.block_urls!(["*/analytics/*"])?
+##
+##         browser |> Browser.navigate_to!("http://localhost:3000")?
+##
+##         error_message = browser |> Browser.find_element!(Css(".error"))?
+##         error_message |> Assert.element_should_have_text!("Could not load the users"),
+## )
+## ```
+##
+## The routes are removed when the test ends - or earlier with `Network.clear_routes!`.
+##
+## The requests of the test are recorded, so you can wait for a response,
+## or check that a request was made with the right payload:
+##
+## ```
+## save_button |> Element.click!()?
+##
+## response = browser |> Network.wait_for_response!("*/api/users")?
+## response.status |> Assert.should_be(201)?
+##
+## requests = browser |> Network.get_requests!()?
+## analytics_calls = requests |> List.keep_if(|{ url }| url |> Str.contains("/analytics/event"))
+## analytics_calls |> Assert.should_have_length(1)?
+## ```
+##
+## Single page apps often keep loading data and rendering long after the page load finished.
+## You can wait for the page to settle down:
+##
+## ```
+## browser |> Browser.navigate_to!("http://localhost:3000/dashboard")?
+## # no requests in flight for 500ms
+## browser |> Browser.wait_for_network_idle!(500)?
+## # no DOM changes for 300ms
+## browser |> Browser.wait_for_dom_stable!(300)?
+## ```
+##
 ## # Env
 ##
 ## Often in E2E tests you need to provide some secret data, like e.g. credentials.
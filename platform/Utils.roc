@@ -3,6 +3,9 @@ module [
     reset_test_log_bucket!,
     get_logs_from_bucket!,
     get_test_name_filter!,
+    get_project_filter!,
+    is_setup_run!,
+    run_setup!,
     set_timeouts!,
     set_window_size!,
     get_assert_timeout!,
@@ -12,9 +15,21 @@ module [
     set_assert_timeout_override!,
     reset_test_overrides!,
     set_window_size_override!,
+    set_network_conditions!,
+    set_network_conditions_override!,
+    set_device!,
+    set_device_override!,
+    set_chrome_options!,
+    set_locale_override!,
+    set_headless_override!,
+    set_results_dir!,
+    set_browser!,
 ]
 
 import Effect
+import Common.NetworkConditions as NetworkConditions exposing [NetworkConditions]
+import Common.Device as Device exposing [Device]
+import Common.ChromeOptions as ChromeOptions exposing [ChromeOptions]
 
 get_time_milis! : {} => U64
 get_time_milis! = |{}|
@@ -37,6 +52,26 @@ get_test_name_filter! = |{}|
     else
         FilterTests(val)
 
+get_project_filter! : {} => [FilterProjects Str, NoFilter]
+get_project_filter! = |{}|
+    val = Effect.get_project_filter!({})
+
+    if val |> Str.is_empty then
+        NoFilter
+    else
+        FilterProjects(val)
+
+# --setup or --print-browser-version-only - the host needs the config to know the browser
+is_setup_run! : {} => Bool
+is_setup_run! = |{}|
+    when Effect.is_setup_run!({}) is
+        1 -> Bool.true
+        _ -> Bool.false
+
+run_setup! : {} => I32
+run_setup! = |{}|
+    Effect.run_setup!({}) |> Num.to_i32
+
 set_timeouts! : { assert_timeout : U64, page_load_timeout : U64, script_execution_timeout : U64, element_implicit_timeout : U64 } => {}
 set_timeouts! = |{ assert_timeout, page_load_timeout, script_execution_timeout, element_implicit_timeout }|
     Effect.set_timeouts!(assert_timeout, page_load_timeout, script_execution_timeout, element_implicit_timeout)
@@ -71,6 +106,46 @@ set_window_size_override! = |Size(x, y)|
     size = "${x |> Num.to_str},${y |> Num.to_str}"
     Effect.set_window_size_override!(size)
 
+set_network_conditions! : NetworkConditions => {}
+set_network_conditions! = |conditions|
+    Effect.set_network_conditions!(NetworkConditions.to_json(conditions))
+
+set_network_conditions_override! : NetworkConditions => {}
+set_network_conditions_override! = |conditions|
+    Effect.set_network_conditions_override!(NetworkConditions.to_json(conditions))
+
+set_device! : Device => {}
+set_device! = |device|
+    Effect.set_device!(Device.to_json(device))
+
+set_device_override! : Device => {}
+set_device_override! = |device|
+    Effect.set_device_override!(Device.to_json(device))
+
+set_chrome_options! : ChromeOptions => {}
+set_chrome_options! = |chrome_options|
+    Effect.set_chrome_options!(ChromeOptions.to_json(chrome_options))
+
+set_locale_override! : Str => {}
+set_locale_override! = |locale|
+    Effect.set_locale_override!(locale)
+
+set_headless_override! : [Yes, No] => {}
+set_headless_override! = |headless|
+    when headless is
+        Yes -> Effect.set_headless_override!(1)
+        No -> Effect.set_headless_override!(0)
+
+set_results_dir! : Str => {}
+set_results_dir! = |dir|
+    Effect.set_results_dir!(dir)
+
+set_browser! : [Chrome, Firefox] => {}
+set_browser! = |browser|
+    when browser is
+        Chrome -> Effect.set_browser!("chrome")
+        Firefox -> Effect.set_browser!("firefox")
+
 get_assert_timeout! : {} => U64
 get_assert_timeout! = |{}|
     Effect.get_assert_timeout!({})
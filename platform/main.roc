@@ -4,7 +4,10 @@ platform ""
         Tutorial,
         Test,
         Browser,
+        Network,
         Element,
+        ShadowRoot,
+        Actions,
         Assert,
         Debug,
         Config,
@@ -28,10 +31,18 @@ main_for_host! = |{}|
         },
     )
     Utils.set_window_size!(config.window_size)
+    Utils.set_network_conditions!(config.network_conditions)
+    Utils.set_device!(config.device)
+    Utils.set_chrome_options!(config.chrome_options)
+    Utils.set_results_dir!(config.results_dir_name)
+    Utils.set_browser!(config.browser)
 
-    when test_cases |> InternalTest.run_tests!(config) is
-        Ok({}) ->
-            0
+    if Utils.is_setup_run!({}) then
+        Utils.run_setup!({})
+    else
+        when test_cases |> InternalTest.run_tests!(config) is
+            Ok({}) ->
+                0
 
-        Err(_) ->
-            1
+            Err(_) ->
+                1
@@ -0,0 +1,110 @@
+app [test_cases, config] { r2e: platform "../platform/main.roc" }
+
+import r2e.Test exposing [test]
+import r2e.Config
+import r2e.Browser
+import r2e.Element
+import r2e.Actions
+import r2e.Assert
+
+config = Config.default_config
+
+test_cases = [
+    test1,
+    test2,
+    test3,
+    test4,
+    test5,
+    test6,
+]
+
+test1 = test(
+    "hover",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!("document.querySelector('#populate').addEventListener('mouseover', () => window.r2eEvent = 'hover');")?
+
+        button = browser |> Browser.find_element!(Css("#populate"))?
+        button |> Element.hover!?
+
+        event = browser |> Browser.execute_js_with_output!("return window.r2eEvent;")?
+        event |> Assert.should_be("hover"),
+)
+
+test2 = test(
+    "double click",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!("document.querySelector('#populate').addEventListener('dblclick', () => window.r2eEvent = 'dblclick');")?
+
+        button = browser |> Browser.find_element!(Css("#populate"))?
+        button |> Element.double_click!?
+
+        event = browser |> Browser.execute_js_with_output!("return window.r2eEvent;")?
+        event |> Assert.should_be("dblclick"),
+)
+
+test3 = test(
+    "right click",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!("document.querySelector('#populate').addEventListener('contextmenu', () => window.r2eEvent = 'contextmenu');")?
+
+        button = browser |> Browser.find_element!(Css("#populate"))?
+        button |> Element.right_click!?
+
+        event = browser |> Browser.execute_js_with_output!("return window.r2eEvent;")?
+        event |> Assert.should_be("contextmenu"),
+)
+
+test4 = test(
+    "drag and drop",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!("document.querySelector('[data-testid=\"name-input\"]').addEventListener('mouseup', () => window.r2eEvent = 'drop');")?
+
+        button = browser |> Browser.find_element!(Css("#populate"))?
+        input = browser |> Browser.find_element!(TestId("name-input"))?
+        button |> Element.drag_and_drop_to!(input)?
+
+        event = browser |> Browser.execute_js_with_output!("return window.r2eEvent;")?
+        event |> Assert.should_be("drop"),
+)
+
+test5 = test(
+    "key chord",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        input = browser |> Browser.find_element!(TestId("name-input"))?
+        input |> Element.input_text!("my name")?
+
+        actions =
+            Actions.new(Mouse)
+            |> Actions.key_down("{ctrl}")
+            |> Actions.press_key("a")
+            |> Actions.key_up("{ctrl}")
+            |> Actions.press_key("x")
+
+        browser |> Actions.perform!(actions)?
+        browser |> Actions.release!?
+
+        input |> Assert.element_should_have_value!("x"),
+)
+
+test6 = test(
+    "scroll",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!("document.body.style.minHeight = '5000px';")?
+
+        actions =
+            Actions.new(Mouse)
+            |> Actions.scroll({ x: 10, y: 10, delta_x: 0, delta_y: 200 })
+            |> Actions.pause(200)
+
+        browser |> Actions.perform!(actions)?
+
+        scrolled = browser |> Browser.execute_js_with_output!("return window.scrollY > 0;")?
+        scrolled |> Assert.should_be(Bool.true),
+)
@@ -1,9 +1,10 @@
 app [test_cases, config] { r2e: platform "../platform/main.roc" }
 
-import r2e.Test exposing [test]
+import r2e.Test exposing [test, test_with]
 import r2e.Config
 import r2e.Browser
 import r2e.Assert
+import r2e.Debug
 
 config = Config.default_config
 
@@ -39,6 +40,33 @@ test_cases = [
     test29,
     test30,
     test31,
+    test32,
+    test33,
+    test34,
+    test35,
+    test36,
+    test37,
+    test38,
+    test39,
+    test40,
+    test41,
+    test42,
+    test43,
+    test44,
+    test45,
+    test46,
+    test47,
+    test48,
+    test49,
+    test50,
+    test51,
+    test52,
+    test53,
+    test54,
+    test55,
+    test56,
+    test57,
+    test58,
 ]
 
 test1 = test(
@@ -481,3 +509,432 @@ test31 = test(
         html = browser |> Browser.get_page_html!?
         html |> Assert.should_contain_text("<h1 class=\"heading\" data-testid=\"header\">Wait for elements</h1>"),
 )
+
+test32 = test(
+    "printPdf",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        pdf_path = browser |> Browser.print_pdf!("example.pdf", {})?
+        pdf_path |> Assert.should_be("testResults/pdf/example.pdf"),
+)
+
+test33 = test(
+    "printPdf with options",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        pdf_path = browser |> Browser.print_pdf!("example-landscape.pdf", { orientation: Landscape, background: Bool.true, scale: 0.5, page_ranges: ["1"] })?
+        pdf_path |> Assert.should_contain_text("example-landscape.pdf"),
+)
+
+test34 = test(
+    "openTab and switchToWindow",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        main_window = browser |> Browser.get_window_handle!?
+
+        tab = browser |> Browser.open_tab!?
+        browser |> Browser.navigate_to!("https://adomurad.github.io/e2e-test-page/waiting")?
+
+        handles = browser |> Browser.get_window_handles!?
+        handles |> Assert.should_have_length(2)?
+        current = browser |> Browser.get_window_handle!?
+        current |> Assert.should_be(tab)?
+
+        remaining = browser |> Browser.close_tab!?
+        remaining |> Assert.should_be([main_window])?
+
+        browser |> Browser.switch_to_window!(main_window)?
+        browser |> Assert.url_should_be!("https://devexpress.github.io/testcafe/example/"),
+)
+
+test35 = test(
+    "waitForNewWindow",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        known_handles = browser |> Browser.get_window_handles!?
+
+        browser |> Browser.execute_js!("setTimeout(() => window.open('https://adomurad.github.io/e2e-test-page/waiting'), 500);")?
+
+        popup = browser |> Browser.wait_for_new_window!(known_handles)?
+        browser |> Browser.switch_to_window!(popup)?
+
+        browser |> Assert.url_should_be!("https://adomurad.github.io/e2e-test-page/waiting"),
+)
+
+test36 = test(
+    "executeJsAsync",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        response = browser |> Browser.execute_js_async!("const done = arguments[arguments.length - 1]; setTimeout(() => done(42), 200);")?
+        response |> Assert.should_be(42),
+)
+
+test37 = test(
+    "executeJsAsyncWithArgs",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        response = browser |> Browser.execute_js_async_with_args!("const [text, done] = arguments; Promise.resolve(text).then(done);", [String("ready")])?
+        response |> Assert.should_be("ready"),
+)
+
+short_script_timeout = test_with({ script_execution_timeout: Override(500) })
+
+test38 = short_script_timeout(
+    "executeJsAsync timeout",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        result : Result Str _
+        result = browser |> Browser.execute_js_async!("const done = arguments[arguments.length - 1]; setTimeout(() => done('late'), 5000);")
+
+        when result is
+            Ok(_) -> Assert.fail_with("should fail")
+            Err(Timeout(_)) -> Ok({})
+            Err(_) -> Assert.fail_with("should fail for different reason"),
+)
+
+test39 = test(
+    "executeJsJson",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        response : List { name : Str, item_count : U64, tags : List Str }
+        response = browser |> Browser.execute_js_json!("return [{ name: 'a', itemCount: 1, tags: [] }, { name: arguments[0], itemCount: 2, tags: ['x', 'y'] }];", [String("b")])?
+
+        response |> Assert.should_be([{ name: "a", item_count: 1, tags: [] }, { name: "b", item_count: 2, tags: ["x", "y"] }]),
+)
+
+test40 = test(
+    "executeJsForElements",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        elements = browser |> Browser.execute_js_for_elements!("return [document.querySelector('h1'), document.querySelector('h1')];", [])?
+        elements |> Assert.should_have_length(2)?
+
+        when elements is
+            [h1, ..] -> h1 |> Assert.element_should_have_text!("Example")
+            _ -> Assert.fail_with("expected elements"),
+)
+
+test41 = test(
+    "executeJsForElements with wrong type",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        when browser |> Browser.execute_js_for_elements!("return 42;", []) is
+            Ok(_) -> Assert.fail_with("should fail")
+            Err(JsReturnTypeError(_)) -> Ok({})
+            Err(_) -> Assert.fail_with("should fail for different reason"),
+)
+
+nested_frames_script =
+    """
+    const outer = document.createElement('iframe');
+    outer.name = 'outer';
+    outer.srcdoc = `<iframe name="middle" srcdoc="<iframe name=&quot;inner&quot; srcdoc=&quot;<span id='deep'>Three frames deep</span>&quot;></iframe>"></iframe>`;
+    document.body.appendChild(outer);
+    """
+
+test42 = test(
+    "useFrameByIndex",
+    |browser|
+        browser |> Browser.navigate_to!("https://adomurad.github.io/e2e-test-page/iframe")?
+
+        Browser.use_frame_by_index!(
+            browser,
+            0,
+            |frame|
+                span = frame |> Browser.find_element!(Css("#span-inside-frame"))?
+                span |> Assert.element_should_have_text!("This is inside an iFrame"),
+        )?
+
+        outside_span = browser |> Browser.find_element!(Css("#span-outside-frame"))?
+        outside_span |> Assert.element_should_have_text!("Outside frame"),
+)
+
+test43 = test(
+    "useFrameByName nested",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!(nested_frames_script)?
+
+        Browser.use_frame_by_name!(
+            browser,
+            "outer",
+            |outer|
+                Browser.use_frame_by_name!(
+                    outer,
+                    "middle",
+                    |middle|
+                        Browser.use_frame_by_name!(
+                            middle,
+                            "inner",
+                            |inner|
+                                span = inner |> Browser.find_element!(Css("#deep"))?
+                                span |> Assert.element_should_have_text!("Three frames deep"),
+                        ),
+                ),
+        )?
+
+        # back in the top-level document
+        browser |> Browser.find_element!(Css("iframe[name=\"outer\"]"))? |> Assert.element_should_be_visible!,
+)
+
+test44 = test(
+    "frame context is restored after leaving nested frames",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!(nested_frames_script)?
+
+        Browser.use_frame_by_name!(
+            browser,
+            "outer",
+            |outer|
+                result = Browser.use_frame_by_name!(
+                    outer,
+                    "middle",
+                    |middle|
+                        middle |> Browser.switch_to_top_level_frame!?
+                        Assert.fail_with("failed in the top-level document"),
+                )
+                result |> Result.is_err |> Assert.should_be(Bool.true)?
+
+                # back in the "outer" frame
+                outer |> Browser.find_element!(Css("iframe[name=\"middle\"]"))? |> Assert.element_should_be_visible!,
+        )?
+
+        Browser.use_frame_by_name!(
+            browser,
+            "outer",
+            |outer|
+                Browser.use_top_level_frame!(
+                    outer,
+                    |page|
+                        page |> Browser.find_element!(Css("iframe[name=\"outer\"]"))? |> Assert.element_should_be_visible!,
+                )?
+
+                outer |> Browser.find_element!(Css("iframe[name=\"middle\"]"))? |> Assert.element_should_be_visible!,
+        ),
+)
+
+test45 = test(
+    "useFrameByName missing frame",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        result = Browser.use_frame_by_name!(browser, "missing", |_frame| Ok({}))
+
+        when result is
+            Ok(_) -> Assert.fail_with("should fail")
+            Err(NoSuchFrame(_)) -> Ok({})
+            Err(_) -> Assert.fail_with("should fail for different reason"),
+)
+
+test46 = test(
+    "frames are reset after navigating back",
+    |browser|
+        browser |> Browser.navigate_to!("https://adomurad.github.io/e2e-test-page/iframe")?
+
+        Browser.use_frame_by_index!(
+            browser,
+            0,
+            |frame|
+                frame |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+                frame |> Browser.navigate_back!,
+        )?
+
+        outside_span = browser |> Browser.find_element!(Css("#span-outside-frame"))?
+        outside_span |> Assert.element_should_have_text!("Outside frame"),
+)
+
+test47 = test(
+    "withTimeouts",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        Browser.with_timeouts!(
+            browser,
+            { script_execution_timeout: Override(500) },
+            |short_browser|
+                result : Result Str _
+                result = short_browser |> Browser.execute_js_async!("const done = arguments[arguments.length - 1]; setTimeout(() => done('late'), 2000);")
+
+                when result is
+                    Ok(_) -> Assert.fail_with("should fail")
+                    Err(Timeout(_)) -> Ok({})
+                    Err(_) -> Assert.fail_with("should fail for different reason"),
+        )?
+
+        # the default script timeout is back
+        response = browser |> Browser.execute_js_async!("const done = arguments[arguments.length - 1]; setTimeout(() => done('on time'), 2000);")?
+        response |> Assert.should_be("on time"),
+)
+
+test48 = test(
+    "withTimeouts restores timeouts after failure",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        result = Browser.with_timeouts!(
+            browser,
+            { script_execution_timeout: Override(500), element_implicit_timeout: Override(0) },
+            |_short_browser| Assert.fail_with("failed with short timeouts"),
+        )
+        result |> Result.is_err |> Assert.should_be(Bool.true)?
+
+        response = browser |> Browser.execute_js_async!("const done = arguments[arguments.length - 1]; setTimeout(() => done('on time'), 2000);")?
+        response |> Assert.should_be("on time"),
+)
+
+test49 = test(
+    "getConsoleLogs",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!("console.error('r2e console error'); setTimeout(() => { throw new Error('r2e uncaught error'); }, 0);")?
+        Debug.wait!(200)
+
+        logs = browser |> Browser.get_console_logs!?
+
+        console_errors = logs |> List.keep_if(|{ level, source, message }| level == Error and source == "console-api" and message |> Str.contains("r2e console error"))
+        console_errors |> Assert.should_have_length(1)?
+
+        exceptions = logs |> List.keep_if(|{ level, source, message }| level == Error and source == "javascript" and message |> Str.contains("r2e uncaught error"))
+        exceptions |> Assert.should_have_length(1)?
+
+        # logs are kept for the whole test - not only the new ones
+        logs_again = browser |> Browser.get_console_logs!?
+        (logs_again |> List.len) |> Assert.should_be_greater_or_equal_to(logs |> List.len),
+)
+
+fail_on_js_errors_test = test_with(
+    {
+        fail_on_js_errors: Override(Yes),
+        js_errors_allow_list: Override(["r2e known (noise|error)"]),
+    },
+)
+
+test50 = fail_on_js_errors_test(
+    "failOnJsErrors - allow-listed errors do not fail the test",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!("console.error('r2e known noise'); setTimeout(() => { throw new Error('r2e known error'); }, 0);")?
+        Debug.wait!(200)
+
+        Ok({}),
+)
+
+test51 = fail_on_js_errors_test(
+    "failOnJsErrors - page without errors",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!("console.log('r2e not an error');")?
+
+        Ok({}),
+)
+
+test52 = test(
+    "waitForNetworkIdle",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!("window.r2eLoaded = false; setTimeout(() => fetch('/testcafe/example/').then((res) => res.text()).then(() => { window.r2eLoaded = true; }), 200);")?
+
+        browser |> Browser.wait_for_network_idle!(500)?
+
+        loaded = browser |> Browser.execute_js_with_output!("return window.r2eLoaded;")?
+        loaded |> Assert.should_be("true"),
+)
+
+test53 = test(
+    "waitForDomStable",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!("let i = 0; const id = setInterval(() => { document.body.setAttribute('data-r2e', i++); if (i > 10) clearInterval(id); }, 50);")?
+
+        browser |> Browser.wait_for_dom_stable!(300)?
+
+        counter = browser |> Browser.execute_js_with_output!("return document.body.getAttribute('data-r2e');")?
+        counter |> Assert.should_be("10"),
+)
+
+test54 = test(
+    "waitForDomStable timeout",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser |> Browser.execute_js!("let i = 0; setInterval(() => { document.body.setAttribute('data-r2e', i++); }, 50);")?
+
+        result = Browser.with_timeouts!(browser, { page_load_timeout: Override(1000) }, |short_browser| short_browser |> Browser.wait_for_dom_stable!(300))
+        when result is
+            Err(Timeout(msg)) -> msg |> Assert.should_contain_text("the DOM was still changing")
+            _ -> Assert.fail_with("should time out"),
+)
+
+iphone_test = test_with({ device: Override(IPhone14) })
+
+test55 = iphone_test(
+    "device emulation preset",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        metrics = browser |> Browser.execute_js_with_output!("return [window.innerWidth, window.devicePixelRatio, navigator.maxTouchPoints > 0, navigator.userAgent.includes('iPhone')].join(',');")?
+        metrics |> Assert.should_be("390,3,true,true"),
+)
+
+custom_device_test = test_with({ device: Override(Custom({ width: 600, height: 900, pixel_ratio: 1.5, user_agent: "r2e-device", touch: Bool.false, mobile: Bool.false })) })
+
+test56 = custom_device_test(
+    "device emulation custom profile",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        metrics = browser |> Browser.execute_js_with_output!("return [window.innerWidth, window.innerHeight, window.devicePixelRatio, navigator.userAgent].join(',');")?
+        metrics |> Assert.should_be("600,900,1.5,r2e-device"),
+)
+
+download_js =
+    """
+    const link = document.createElement('a');
+    link.href = URL.createObjectURL(new Blob(['id,name\\n1,Bob'], { type: 'text/csv' }));
+    link.download = 'report-2024.csv';
+    document.body.appendChild(link);
+    link.click();
+    """
+
+test57 = test(
+    "wait for download and read it",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        browser |> Browser.execute_js!(download_js)?
+
+        file_path = browser |> Browser.wait_for_download!("report-*.csv")?
+        file_path |> Assert.should_contain_text("report-2024.csv")?
+
+        files = browser |> Browser.get_downloads!()?
+        files |> Assert.should_be(["report-2024.csv"])?
+
+        content = browser |> Browser.read_download!("report-2024.csv")?
+        content |> Assert.should_be(Str.to_utf8("id,name\n1,Bob")),
+)
+
+test58 = test(
+    "assert file downloaded",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        # every test has its own download directory
+        files = browser |> Browser.get_downloads!()?
+        files |> Assert.should_be([])?
+
+        browser |> Browser.execute_js!(download_js)?
+        browser |> Assert.file_downloaded!("*.csv")?
+
+        when browser |> Assert.file_downloaded!("*.xlsx") is
+            Ok(_) -> Assert.fail_with("should fail")
+            Err(AssertionError(err)) -> err |> Assert.should_contain_text("no file matching \"*.xlsx\" was downloaded")
+            Err(_) -> Assert.fail_with("should fail for different reason"),
+)
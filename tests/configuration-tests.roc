@@ -40,6 +40,14 @@ config = Config.default_config_with(
         window_size: Size(500, 500),
         screenshot_on_fail: No,
         attempts: 3,
+        chrome_options: Config.chrome_options(
+            {
+                args: ["--user-agent=r2e-chrome-arg"],
+                prefs: [{ name: "intl.accept_languages", value: String("fr-FR") }],
+                exclude_switches: ["enable-automation"],
+                extensions: ["./tests/test-extension"],
+            },
+        ),
     },
 )
 
@@ -52,6 +60,7 @@ test_cases = [
     test6,
     test7,
     test8,
+    test9,
 ]
 
 test1_override = Test.test_with(
@@ -225,3 +234,18 @@ test8 = custom_test(
                 else
                     Assert.fail_with((err |> Inspect.to_str)),
 )
+
+test9 = test(
+    "chrome options",
+    |browser|
+        browser |> Browser.navigate_to!("https://adomurad.github.io/e2e-test-page/")?
+
+        user_agent = browser |> Browser.execute_js_with_output!("return navigator.userAgent;")?
+        user_agent |> Assert.should_be("r2e-chrome-arg")?
+
+        languages = browser |> Browser.execute_js_with_output!("return navigator.languages.join(',');")?
+        languages |> Assert.should_contain_text("fr-FR")?
+
+        extension = browser |> Browser.execute_js_with_output!("return document.documentElement.getAttribute('data-r2e-extension');")?
+        extension |> Assert.should_be("loaded"),
+)
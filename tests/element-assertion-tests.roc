@@ -13,6 +13,10 @@ test_cases = [
     test2,
     test3,
     test4,
+    test5,
+    test6,
+    test7,
+    test8,
 ]
 
 test1 = test(
@@ -69,4 +73,62 @@ test4 = test(
             Err(err) -> Assert.should_be((err |> Inspect.to_str), "(AssertionError \"Expected element (Css \"#show-opacity\") to have text \"fail\", but got \"Show via opacity\" (waited for 3000ms)\")"),
 )
 
+test5 = test(
+    "elementShouldBeEnabled and elementShouldBeDisabled",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        comments = browser |> Browser.find_element!(Css("#comments"))?
+        comments |> Assert.element_should_be_disabled!?
+
+        checkbox = browser |> Browser.find_element!(Css("#tried-test-cafe"))?
+        checkbox |> Element.click!?
+
+        comments |> Assert.element_should_be_enabled!,
+)
+
+test6 = test(
+    "elementShouldBeEnabled timeout",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        comments = browser |> Browser.find_element!(Css("#comments"))?
+        result = comments |> Assert.element_should_be_enabled!()
+        when result is
+            Ok(_) -> Assert.fail_with("should not be enabled")
+            Err(err) -> Assert.should_be((err |> Inspect.to_str), "(AssertionError \"Expected element (Css \"#comments\") to be enabled (waited for 3000ms)\")"),
+)
+
+test7 = test(
+    "elementShouldBeFocused",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        input = browser |> Browser.find_element!(Css("#developer-name"))?
+        input |> Element.click!?
+
+        input |> Assert.element_should_be_focused!?
+
+        button = browser |> Browser.find_element!(Css("#populate"))?
+        result = button |> Assert.element_should_be_focused!()
+        when result is
+            Ok(_) -> Assert.fail_with("should not be focused")
+            Err(err) -> Assert.should_be((err |> Inspect.to_str), "(AssertionError \"Expected element (Css \"#populate\") to be focused (waited for 3000ms)\")"),
+)
+
+test8 = test(
+    "elementShouldHaveAccessibleRole and elementShouldHaveAccessibleName",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        button = browser |> Browser.find_element!(Css("#populate"))?
+        button |> Assert.element_should_have_accessible_role!("button")?
+        button |> Assert.element_should_have_accessible_name!("Populate")?
+
+        result = button |> Assert.element_should_have_accessible_name!("Submit")
+        when result is
+            Ok(_) -> Assert.fail_with("should fail")
+            Err(err) -> Assert.should_be((err |> Inspect.to_str), "(AssertionError \"Expected element (Css \"#populate\") to have accessible name \"Submit\", but got \"Populate\" (waited for 3000ms)\")"),
+)
+
 # TODO test Assert.elementShouldHaveValue
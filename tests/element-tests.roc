@@ -4,6 +4,7 @@ import r2e.Test exposing [test]
 import r2e.Config
 import r2e.Browser
 import r2e.Element
+import r2e.ShadowRoot
 import r2e.Assert
 
 config = Config.default_config
@@ -55,6 +56,17 @@ test_cases = [
     test44,
     test45,
     test46,
+    test47,
+    test48,
+    test49,
+    test50,
+    test51,
+    test52,
+    test53,
+    test54,
+    test55,
+    test56,
+    test57,
 ]
 
 test1 = test(
@@ -687,3 +699,149 @@ test46 = test(
                 span |> Assert.element_should_have_text!("This is inside an iFrame"),
         ),
 )
+
+test47 = test(
+    "stale element reference",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        input = browser |> Browser.find_element!(TestId("name-input"))?
+
+        browser |> Browser.reload_page!?
+
+        when input |> Element.get_text! is
+            Ok(_) -> Assert.fail_with("should fail")
+            Err(StaleElementReference(_)) -> Ok({})
+            Err(_) -> Assert.fail_with("should fail for different reason"),
+)
+
+test48 = test(
+    "invalid selector",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        when browser |> Browser.find_element!(XPath("//div[")) is
+            Ok(_) -> Assert.fail_with("should fail")
+            Err(InvalidSelector(_)) -> Ok({})
+            Err(_) -> Assert.fail_with("should fail for different reason"),
+)
+
+test49 = test(
+    "inputText {backspace} and arrows",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        input = browser |> Browser.find_element!(TestId("name-input"))?
+
+        input |> Element.input_text!("abcd{backspace}{left}{left}X{end}Y")?
+
+        input |> Assert.element_should_have_value!("aXbcY"),
+)
+
+test50 = test(
+    "inputText chords",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        input = browser |> Browser.find_element!(TestId("name-input"))?
+
+        input |> Element.input_text!("first{ctrl+a}second{shift+left}{backspace}")?
+
+        input |> Assert.element_should_have_value!("secon"),
+)
+
+test51 = test(
+    "inputText escaped braces",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        input = browser |> Browser.find_element!(TestId("name-input"))?
+
+        input |> Element.input_text!("{{enter}} {unknown}")?
+
+        input |> Assert.element_should_have_value!("{enter} {unknown}"),
+)
+
+test52 = test(
+    "takeScreenshotBase64 of element",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        button = browser |> Browser.find_element!(Css("#populate"))?
+
+        screenshot = button |> Element.take_screenshot_base64!?
+        # base64 encoded PNG signature
+        screenshot |> Str.starts_with("iVBORw0KGgo") |> Assert.should_be(Bool.true),
+)
+
+test53 = test(
+    "shadow root",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        browser
+        |> Browser.execute_js!(
+            """
+            const host = document.createElement('div');
+            host.id = 'shadow-host';
+            document.body.appendChild(host);
+            const root = host.attachShadow({ mode: 'open' });
+            root.innerHTML = '<span class="inside">Inside shadow</span><span class="inside">Second</span>';
+            """,
+        )?
+
+        host = browser |> Browser.find_element!(Css("#shadow-host"))?
+        shadow_root = host |> Element.get_shadow_root!?
+
+        span = shadow_root |> ShadowRoot.find_element!(Css(".inside"))?
+        span |> Assert.element_should_have_text!("Inside shadow")?
+
+        spans = shadow_root |> ShadowRoot.find_elements!(Css(".inside"))?
+        spans |> Assert.should_have_length(2)?
+
+        when shadow_root |> ShadowRoot.try_find_element!(Css(".missing"))? is
+            NotFound -> Ok({})
+            Found(_) -> Assert.fail_with("should not find an element"),
+)
+
+test54 = test(
+    "shadow root not found",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        h1 = browser |> Browser.find_element!(Css("h1"))?
+
+        when h1 |> Element.get_shadow_root! is
+            Ok(_) -> Assert.fail_with("should fail")
+            Err(ShadowRootNotFound(_)) -> Ok({})
+            Err(_) -> Assert.fail_with("should fail for different reason"),
+)
+
+test55 = test(
+    "isEnabled",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        comments = browser |> Browser.find_element!(Css("#comments"))?
+
+        comments |> Element.is_enabled!? |> Assert.should_be(Disabled)?
+
+        checkbox = browser |> Browser.find_element!(Css("#tried-test-cafe"))?
+        checkbox |> Element.click!?
+
+        comments |> Element.is_enabled!? |> Assert.should_be(Enabled),
+)
+
+test56 = test(
+    "getAccessibleRole and getAccessibleName",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        button = browser |> Browser.find_element!(Css("#populate"))?
+
+        button |> Element.get_accessible_role!? |> Assert.should_be("button")?
+        button |> Element.get_accessible_name!? |> Assert.should_be("Populate"),
+)
+
+test57 = test(
+    "getActiveElement",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        input = browser |> Browser.find_element!(TestId("name-input"))?
+        input |> Element.click!?
+
+        active = browser |> Browser.get_active_element!?
+
+        active |> Element.get_attribute!("id")? |> Assert.should_be("developer-name"),
+)
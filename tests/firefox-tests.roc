@@ -0,0 +1,49 @@
+app [test_cases, config] { r2e: platform "../platform/main.roc" }
+
+import r2e.Test exposing [test]
+import r2e.Config
+import r2e.Browser
+import r2e.Element
+import r2e.Assert
+
+config = Config.default_config_with({ browser: Firefox })
+
+test_cases = [
+    test1,
+    test2,
+    test3,
+]
+
+test1 = test(
+    "runs in firefox",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        user_agent : Str
+        user_agent = browser |> Browser.execute_js_with_output!("return navigator.userAgent;")?
+        user_agent |> Assert.should_contain_text("Firefox"),
+)
+
+test2 = test(
+    "getTitle in firefox",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+        title = browser |> Browser.get_title!?
+
+        title |> Assert.should_be("TestCafe Example Page"),
+)
+
+test3 = test(
+    "inputText and click in firefox",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        input = browser |> Browser.find_element!(TestId("name-input"))?
+        input |> Element.input_text!("Roc")?
+        input |> Assert.element_should_have_value!("Roc")?
+
+        button = browser |> Browser.find_element!(Css("#submit-button"))?
+        button |> Element.click!?
+
+        browser |> Browser.find_element!(TestId("thank-you-header"))? |> Assert.element_should_have_text!("Thank you, Roc!"),
+)
@@ -0,0 +1,198 @@
+app [test_cases, config] { r2e: platform "../platform/main.roc" }
+
+import r2e.Test exposing [test, test_with]
+import r2e.Config
+import r2e.Browser
+import r2e.Network
+import r2e.Assert
+
+config = Config.default_config
+
+test_cases = [
+    test1,
+    test2,
+    test3,
+    test4,
+    test5,
+    test6,
+    test7,
+    test8,
+    test9,
+    test10,
+    test11,
+]
+
+# the test page origin - fetch from the same origin to avoid CORS
+page_url = "https://devexpress.github.io/testcafe/example/"
+
+fetch_js = |url|
+    """
+    const done = arguments[arguments.length - 1];
+    fetch("${url}")
+        .then(async (res) => done(res.status + " " + (await res.text())))
+        .catch(() => done("failed"));
+    """
+
+test1 = test(
+    "mockResponse with a text body",
+    |browser|
+        browser |> Browser.navigate_to!(page_url)?
+        browser |> Network.mock_response!("*/r2e-mocked-api*", { status: 500, headers: [{ name: "Content-Type", value: "application/json" }], body: Text("{\"error\":\"mocked\"}") })?
+
+        response = browser |> Browser.execute_js_async!(fetch_js("/r2e-mocked-api/users"))?
+        response |> Assert.should_be("500 {\"error\":\"mocked\"}"),
+)
+
+test2 = test(
+    "mockResponse with a file body",
+    |browser|
+        browser |> Browser.navigate_to!(page_url)?
+        browser |> Network.mock_response!("*/r2e-mocked-file", { body: File("./tests/network-tests.roc") })?
+
+        response = browser |> Browser.execute_js_async!(fetch_js("/r2e-mocked-file"))?
+        response |> Assert.should_contain_text("200 app [test_cases, config]"),
+)
+
+test3 = test(
+    "blockUrls",
+    |browser|
+        browser |> Browser.navigate_to!(page_url)?
+        browser |> Network.block_urls!(["*/r2e-blocked/*", "*.r2e-blocked"])?
+
+        response1 = browser |> Browser.execute_js_async!(fetch_js("/r2e-blocked/data"))?
+        response1 |> Assert.should_be("failed")?
+
+        response2 = browser |> Browser.execute_js_async!(fetch_js("/file.r2e-blocked"))?
+        response2 |> Assert.should_be("failed"),
+)
+
+test4 = test(
+    "the most recent route wins",
+    |browser|
+        browser |> Network.block_urls!(["*/r2e-route/*"])?
+        browser |> Browser.navigate_to!(page_url)?
+        browser |> Network.mock_response!("*/r2e-route/second", { body: Text("second") })?
+
+        response1 = browser |> Browser.execute_js_async!(fetch_js("/r2e-route/first"))?
+        response1 |> Assert.should_be("failed")?
+
+        response2 = browser |> Browser.execute_js_async!(fetch_js("/r2e-route/second"))?
+        response2 |> Assert.should_be("200 second"),
+)
+
+test5 = test(
+    "modifyRequest",
+    |browser|
+        browser |> Browser.navigate_to!(page_url)?
+        browser |> Network.modify_request!("*/r2e-missing-page", { url: page_url })?
+
+        response = browser |> Browser.execute_js_async!(fetch_js("/r2e-missing-page"))?
+        response |> Assert.should_contain_text("200 ")?
+        response |> Assert.should_contain_text("TestCafe Example Page"),
+)
+
+test6 = test(
+    "clearRoutes",
+    |browser|
+        browser |> Browser.navigate_to!(page_url)?
+        browser |> Network.block_urls!(["*/testcafe/example/*"])?
+        browser |> Network.clear_routes!?
+
+        response = browser |> Browser.execute_js_async!(fetch_js("/testcafe/example/"))?
+        response |> Assert.should_contain_text("TestCafe Example Page"),
+)
+
+test7 = test(
+    "waitForResponse",
+    |browser|
+        browser |> Browser.navigate_to!(page_url)?
+        browser |> Network.mock_response!("*/r2e-wait/*", { status: 201, headers: [{ name: "X-R2E", value: "recorded" }], body: Text("created") })?
+        browser |> Browser.execute_js!("setTimeout(() => fetch('/r2e-wait/users', { method: 'POST' }), 500);")?
+
+        response = browser |> Network.wait_for_response!("*/r2e-wait/*")?
+        response.status |> Assert.should_be(201)?
+        response.body |> Assert.should_be("created")?
+        response.url |> Assert.should_contain_text("/r2e-wait/users")?
+
+        header = response.headers |> List.find_first(|{ name }| name == "X-R2E")
+        header |> Assert.should_be(Ok({ name: "X-R2E", value: "recorded" }))?
+
+        # every response is returned only once
+        result = browser |> Network.wait_for_response!("*/r2e-wait/*")
+        when result is
+            Err(Timeout(_)) -> Ok({})
+            _ -> Assert.fail_with("the response should be returned only once"),
+)
+
+test8 = test(
+    "getRequests",
+    |browser|
+        browser |> Browser.navigate_to!(page_url)?
+        browser |> Network.mock_response!("*/r2e-analytics", { status: 204 })?
+        _ = browser |> Browser.execute_js_async!("const done = arguments[arguments.length - 1]; fetch('/r2e-analytics', { method: 'POST', body: '{\\"event\\":\\"click\\"}' }).then(() => done('ok'));")?
+
+        requests = browser |> Network.get_requests!?
+
+        document_requests = requests |> List.keep_if(|{ resource_type, url }| resource_type == "Document" and url == page_url)
+        document_requests |> Assert.should_have_length(1)?
+
+        analytics_requests = requests |> List.keep_if(|{ url }| url |> Str.contains("/r2e-analytics"))
+        analytics_requests |> Assert.should_have_length(1)?
+
+        when analytics_requests is
+            [{ method, post_data, response }] ->
+                method |> Assert.should_be("POST")?
+                post_data |> Assert.should_be("{\"event\":\"click\"}")?
+                when response is
+                    Received({ status }) -> status |> Assert.should_be(204)
+                    _ -> Assert.fail_with("expected a response")
+
+            _ -> Assert.fail_with("expected a single analytics request"),
+)
+
+test9 = test(
+    "getRequests with a blocked request",
+    |browser|
+        browser |> Browser.navigate_to!(page_url)?
+        browser |> Network.block_urls!(["*/r2e-blocked-recorded"])?
+        _ = browser |> Browser.execute_js_async!("const done = arguments[arguments.length - 1]; fetch('/r2e-blocked-recorded').catch(() => done('failed'));")?
+
+        requests = browser |> Network.get_requests!?
+        blocked = requests |> List.keep_if(|{ url }| url |> Str.contains("/r2e-blocked-recorded"))
+
+        when blocked is
+            [{ response: Failed(error) }] -> error |> Assert.should_contain_text("ERR_BLOCKED_BY_CLIENT")
+            _ -> Assert.fail_with("expected a single failed request"),
+)
+
+test10 = test(
+    "setNetworkConditions offline",
+    |browser|
+        browser |> Browser.navigate_to!(page_url)?
+        browser |> Browser.set_network_conditions!(Offline)?
+
+        response1 = browser |> Browser.execute_js_async!(fetch_js("/testcafe/example/"))?
+        response1 |> Assert.should_be("failed")?
+
+        browser |> Browser.set_network_conditions!(NoThrottling)?
+
+        response2 = browser |> Browser.execute_js_async!(fetch_js("/testcafe/example/"))?
+        response2 |> Assert.should_contain_text("TestCafe Example Page"),
+)
+
+slow_network_test = test_with(
+    {
+        network_conditions: Override(Custom({ latency: 1000, download_kbps: 100_000, upload_kbps: 100_000 })),
+    },
+)
+
+test11 = slow_network_test(
+    "networkConditions override",
+    |browser|
+        browser |> Browser.navigate_to!(page_url)?
+
+        duration_str = browser |> Browser.execute_js_async!("const done = arguments[arguments.length - 1]; const start = Date.now(); fetch('/testcafe/example/', { cache: 'no-store' }).then(() => done(String(Date.now() - start)));")?
+        duration = duration_str |> Str.to_u64 |> Result.with_default(0)
+
+        duration |> Assert.should_be_greater_or_equal_to(1000),
+)
@@ -0,0 +1,48 @@
+app [test_cases, config] { r2e: platform "../platform/main.roc" }
+
+import r2e.Test exposing [test]
+import r2e.Reporting
+import r2e.Config
+import r2e.Browser
+import r2e.Debug
+
+project_reporter = Reporting.create_reporter(
+    "projectReporter",
+    |results, _meta|
+        results
+        |> List.map(
+            # the logs of the test are compared with the expected values of each project in run-all-tests.sh
+            |{ project, logs }|
+                content = logs |> Str.join_with("\n")
+                when project is
+                    Project(project_name) -> { file_path: "project-${project_name}.txt", content }
+                    NoProject -> { file_path: "no-project.txt", content },
+        ),
+)
+
+config = Config.default_config_with(
+    {
+        results_dir_name: "testProjectsDir",
+        reporters: [project_reporter],
+        window_size: Size(1024, 768),
+        projects: [
+            Config.project("desktop-fr", { locale: Override("fr-FR") }),
+            Config.project("phone-de", { device: Override(IPhone14), locale: Override("de-DE") }),
+        ],
+    },
+)
+
+test_cases = [
+    test1,
+]
+
+test1 = test(
+    "project overrides reach the browser",
+    |browser|
+        browser |> Browser.navigate_to!("https://devexpress.github.io/testcafe/example/")?
+
+        metrics = browser |> Browser.execute_js_with_output!("return [window.innerWidth === 390, navigator.userAgent.includes('iPhone'), navigator.language].join(',');")?
+        Debug.print_line!(metrics)
+
+        Ok({}),
+)
@@ -18,11 +18,20 @@ roc $TEST_DIR/browser-tests.roc --headless || exit 1;
 echo "Running element-tests.roc"
 roc --linker=legacy $TEST_DIR/element-tests.roc --headless || exit 1;
 
+echo "Running actions-tests.roc"
+roc $TEST_DIR/actions-tests.roc --headless || exit 1;
+
+echo "Running network-tests.roc"
+roc $TEST_DIR/network-tests.roc --headless || exit 1;
+
+echo "Running firefox-tests.roc"
+roc $TEST_DIR/firefox-tests.roc --headless || exit 1;
+
 echo "removing the test dir" # should auto remove?
 rm -rf testTestDir78
 
 echo "Running configuration-tests.roc"
-roc $TEST_DIR/configuration-tests.roc --headless || exit 1;
+roc $TEST_DIR/configuration-tests.roc --headless --chrome-arg=--disable-gpu || exit 1;
 
 if [ -e ./testTestDir78/basicRenamed/index.html ]; then
     echo "reports ok"
@@ -38,6 +47,18 @@ else
     exit 1
 fi
 
+rm -rf testProjectsDir
+
+echo "Running projects-tests.roc"
+roc $TEST_DIR/projects-tests.roc --headless || exit 1;
+
+if [ "$(cat ./testProjectsDir/projectReporter/project-desktop-fr.txt)" == "false,false,fr-FR" ] && [ "$(cat ./testProjectsDir/projectReporter/project-phone-de.txt)" == "true,true,de-DE" ]; then
+    echo "projects ok"
+else
+    echo "the project overrides were not applied"
+    exit 1
+fi
+
 echo "Running element-assertion-tests.roc"
 roc $TEST_DIR/element-assertion-tests.roc --headless || exit 1;
 
@@ -0,0 +1,2 @@
+// marks the page, so the tests can check that the extension was loaded
+document.documentElement.setAttribute("data-r2e-extension", "loaded");